@@ -0,0 +1,180 @@
+package semanticid
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+)
+
+var _ encoding.BinaryMarshaler = &SemanticID{}
+var _ encoding.BinaryUnmarshaler = &SemanticID{}
+
+// The kind of ID part that follows the namespace and collection in
+// the binary form of a SemanticID.
+const (
+	binaryIDString byte = iota
+	binaryIDProvider
+)
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface for
+// SemanticID. See Codec.EncodeBinary for a description of the format.
+func (sid SemanticID) MarshalBinary() ([]byte, error) {
+	return defaultCodec().EncodeBinary(sid)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+// for SemanticID.
+func (sid *SemanticID) UnmarshalBinary(b []byte) error {
+	return defaultCodec().DecodeBinary(b, sid)
+}
+
+// EncodeBinary encodes the given SemanticID into a compact binary form.
+// Namespace and collection are each prefixed with their length as a
+// uvarint, followed by a single byte that marks the kind of ID part. If
+// the SemanticID's provider implements BinaryIDProvider, the ID part is
+// stored in its binary form (16 bytes for a ULID or UUID), otherwise the
+// raw bytes of the ID string are used. Nil SemanticIDs are encoded as
+// an empty slice.
+func (c *Codec) EncodeBinary(sid SemanticID) ([]byte, error) {
+	if sid.IsNil() {
+		return []byte{}, nil
+	}
+
+	kind := binaryIDString
+	id, ok, err := c.idToBytes(sid)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		kind = binaryIDProvider
+	} else {
+		id = []byte(sid.ID)
+	}
+
+	result := make([]byte, 0, 2*binary.MaxVarintLen64+len(sid.Namespace)+len(sid.Collection)+1+len(id))
+	result = appendBinaryPart(result, sid.Namespace)
+	result = appendBinaryPart(result, sid.Collection)
+	result = append(result, kind)
+	result = append(result, id...)
+
+	return result, nil
+}
+
+// DecodeBinary parses the binary form created by EncodeBinary into the
+// given SemanticID. The ID part is validated using the provider
+// selected for the decoded namespace and collection. Empty bytes are
+// decoded into a nil SemanticID.
+func (c *Codec) DecodeBinary(b []byte, sid *SemanticID) error {
+	if len(b) == 0 {
+		*sid = SemanticID{}
+		return nil
+	}
+
+	namespace, rest, ok := readBinaryPart(b)
+	if !ok {
+		return invalidBinary()
+	}
+
+	collection, rest, ok := readBinaryPart(rest)
+	if !ok || len(rest) == 0 {
+		return invalidBinary()
+	}
+
+	parsed, err := c.newFromParts(namespace, collection, "")
+	if err != nil {
+		return err
+	}
+
+	kind, rest := rest[0], rest[1:]
+
+	switch kind {
+	case binaryIDString:
+		parsed.ID = string(rest)
+	case binaryIDProvider:
+		parsed.ID, err = c.idFromBytes(namespace, collection, rest)
+		if err != nil {
+			return err
+		}
+	default:
+		return invalidBinary()
+	}
+
+	parsed.ID, err = checkID(c.providerFor(namespace, collection), parsed.ID, c.String(parsed))
+	if err != nil {
+		return err
+	}
+
+	*sid = parsed
+	return nil
+}
+
+// idFromBytes converts the binary form of an ID part back to its string
+// form, using the provider selected for the namespace and collection.
+func (c *Codec) idFromBytes(namespace, collection string, b []byte) (string, error) {
+	bp, ok := c.providerFor(namespace, collection).(BinaryIDProvider)
+	if !ok {
+		return "", &SemanticIDError{
+			errCode: errInvalidID,
+			message: fmt.Sprintf(
+				"The ID provider for %s%s%s has no binary form",
+				namespace,
+				c.separator,
+				collection,
+			),
+		}
+	}
+
+	id, err := bp.IDFromBytes(b)
+	if err != nil {
+		return "", &SemanticIDError{
+			errCode: errInvalidID,
+			message: fmt.Sprintf("The binary ID for %s%s%s is invalid", namespace, c.separator, collection),
+		}
+	}
+
+	return id, nil
+}
+
+// idToBytes converts the ID part of the SemanticID to its binary form,
+// if its provider has one.
+func (c *Codec) idToBytes(sid SemanticID) ([]byte, bool, error) {
+	bp, ok := c.providerFor(sid.Namespace, sid.Collection).(BinaryIDProvider)
+	if !ok {
+		return nil, false, nil
+	}
+
+	b, err := bp.IDToBytes(sid.ID)
+	if err != nil {
+		return nil, false, &SemanticIDError{
+			errCode: errInvalidID,
+			message: fmt.Sprintf("The UUID section for %s is invalid", c.String(sid)),
+		}
+	}
+
+	return b, true, nil
+}
+
+func appendBinaryPart(b []byte, part string) []byte {
+	var length [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(length[:], uint64(len(part)))
+	b = append(b, length[:n]...)
+	return append(b, part...)
+}
+
+func readBinaryPart(b []byte) (string, []byte, bool) {
+	length, n := binary.Uvarint(b)
+	if n <= 0 || length > uint64(len(b)-n) {
+		return "", nil, false
+	}
+
+	end := n + int(length)
+	return string(b[n:end]), b[end:], true
+}
+
+func invalidBinary() error {
+	return &SemanticIDError{
+		errCode: errInvalidSID,
+		message: "The given bytes are not a valid semantic id",
+	}
+}
@@ -0,0 +1,131 @@
+package semanticid_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("binary", func() {
+	Describe("Encoding semanticids as binary", func() {
+		It("should store ULIDs as 16 bytes", func() {
+			sid := semanticid.Must(semanticid.New("ns", "col"))
+
+			b, err := sid.MarshalBinary()
+			Expect(err).To(BeNil())
+			Expect(b).To(HaveLen(1 + 2 + 1 + 3 + 1 + 16))
+
+			var result semanticid.SemanticID
+			Expect(result.UnmarshalBinary(b)).To(BeNil())
+			Expect(result).To(Equal(sid))
+		})
+
+		It("should store UUIDs as 16 bytes", func() {
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(semanticid.NewUUIDProvider()))
+			sid := semanticid.Must(codec.New("ns", "col"))
+
+			b, err := codec.EncodeBinary(sid)
+			Expect(err).To(BeNil())
+			Expect(b).To(HaveLen(1 + 2 + 1 + 3 + 1 + 16))
+
+			var result semanticid.SemanticID
+			Expect(codec.DecodeBinary(b, &result)).To(BeNil())
+			Expect(result).To(Equal(sid))
+		})
+
+		It("should store snowflake IDs as 8 bytes", func() {
+			provider, err := semanticid.NewSnowflakeProvider(1,
+				semanticid.WithSnowflakeEncoding(semanticid.SnowflakeBase62))
+			Expect(err).To(BeNil())
+
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(provider))
+			sid := semanticid.Must(codec.New("ns", "col"))
+
+			b, err := codec.EncodeBinary(sid)
+			Expect(err).To(BeNil())
+			Expect(b).To(HaveLen(1 + 2 + 1 + 3 + 1 + 8))
+
+			var result semanticid.SemanticID
+			Expect(codec.DecodeBinary(b, &result)).To(BeNil())
+			Expect(result).To(Equal(sid))
+		})
+
+		It("should fall back to the raw ID for other providers", func() {
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(&TestProvider{}))
+			sid := semanticid.Must(codec.New("ns", "col"))
+
+			b, err := codec.EncodeBinary(sid)
+			Expect(err).To(BeNil())
+			Expect(bytes.HasSuffix(b, []byte("1234"))).To(BeTrue())
+
+			var result semanticid.SemanticID
+			Expect(codec.DecodeBinary(b, &result)).To(BeNil())
+			Expect(result).To(Equal(sid))
+		})
+
+		It("should encode nil semanticids as empty bytes", func() {
+			b, err := semanticid.SemanticID{}.MarshalBinary()
+			Expect(err).To(BeNil())
+			Expect(b).To(BeEmpty())
+
+			result := semanticid.Must(semanticid.NewDefault())
+			Expect(result.UnmarshalBinary(b)).To(BeNil())
+			Expect(result.IsNil()).To(BeTrue())
+		})
+
+		It("should reject IDs that are invalid for the provider", func() {
+			sid := semanticid.SemanticID{Namespace: "ns", Collection: "col", ID: "invalid"}
+
+			_, err := sid.MarshalBinary()
+			Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+		})
+
+		It("should reject malformed input", func() {
+			sid := semanticid.Must(semanticid.New("ns", "col"))
+			b, err := sid.MarshalBinary()
+			Expect(err).To(BeNil())
+
+			var result semanticid.SemanticID
+			err = result.UnmarshalBinary(b[:3])
+			Expect(errors.Is(err, semanticid.ErrInvalid)).To(BeTrue())
+
+			err = result.UnmarshalBinary(b[:len(b)-1])
+			Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+
+			corrupted := append([]byte{}, b...)
+			corrupted[7] = 0xff
+			err = result.UnmarshalBinary(corrupted)
+			Expect(errors.Is(err, semanticid.ErrInvalid)).To(BeTrue())
+		})
+
+		It("should reject raw IDs that are invalid for the provider", func() {
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(&TestProvider{}))
+			b, err := codec.EncodeBinary(semanticid.SemanticID{Namespace: "ns", Collection: "col", ID: "invalid"})
+			Expect(err).To(BeNil())
+
+			var result semanticid.SemanticID
+			err = result.UnmarshalBinary(b)
+			Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+		})
+
+		It("should work with gob", func() {
+			type model struct {
+				ID semanticid.SemanticID
+			}
+
+			original := model{ID: semanticid.Must(semanticid.NewDefault())}
+
+			var buf bytes.Buffer
+			Expect(gob.NewEncoder(&buf).Encode(original)).To(BeNil())
+
+			var result model
+			Expect(gob.NewDecoder(&buf).Decode(&result)).To(BeNil())
+			Expect(result).To(Equal(original))
+		})
+	})
+})
@@ -3,22 +3,131 @@ package semanticid
 import (
 	"fmt"
 	"reflect"
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonoptions"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
 )
 
 var pointerType = reflect.TypeOf(&SemanticID{})
 var rawType = reflect.TypeOf(SemanticID{})
 
-// BSONSemanticIDCodec is a mongodb ValueCodec for
-// encoding and decoding SemanticIDs to and from BSON.
-type BSONSemanticIDCodec struct{}
+// BSONStorage determines how the BSON codecs store SemanticIDs.
+type BSONStorage int
+
+const (
+	// BSONStorageString stores SemanticIDs as BSON strings, using their
+	// string representation. This is the default.
+	BSONStorageString BSONStorage = iota
+	// BSONStorageDocument stores SemanticIDs as BSON subdocuments of the
+	// form `{ns: <namespace>, coll: <collection>, id: <binary>}`. If the
+	// ID provider implements BinaryIDProvider, the ID part is stored as
+	// binary (16 bytes for a ULID or UUID), otherwise it is stored as a
+	// string. Namespace and collection stay queryable.
+	BSONStorageDocument
+	// BSONStorageBinary stores SemanticIDs as a single BSON binary value
+	// in the format produced by Codec.EncodeBinary. This is the most
+	// compact form and results in the smallest indexes.
+	BSONStorageBinary
+)
+
+// Field names used by BSONStorageDocument.
+const (
+	bsonNamespaceField  = "ns"
+	bsonCollectionField = "coll"
+	bsonIDField         = "id"
+)
 
 // BSONSemanticIDCodec is a mongodb ValueCodec for
 // encoding and decoding SemanticIDs to and from BSON.
-type BSONSemanticIDPointerCodec struct{}
+// The zero value uses the package-level settings. Regardless of
+// the storage, all supported forms are accepted when decoding,
+// so existing data can be migrated gradually.
+type BSONSemanticIDCodec struct {
+	codec   *Codec
+	storage BSONStorage
+}
+
+// BSONSemanticIDPointerCodec is a mongodb ValueCodec for
+// encoding and decoding SemanticID pointers to and from BSON.
+// The zero value uses the package-level settings.
+type BSONSemanticIDPointerCodec struct {
+	codec   *Codec
+	storage BSONStorage
+}
+
+// BSONCodecOption configures the BSON codecs.
+type BSONCodecOption func(*bsonCodecOptions)
+
+type bsonCodecOptions struct {
+	storage    BSONStorage
+	mapOptions []*bsonoptions.MapCodecOptions
+}
+
+// WithBSONStorage selects how SemanticIDs are stored. By default,
+// BSONStorageString is used.
+func WithBSONStorage(storage BSONStorage) BSONCodecOption {
+	return func(o *bsonCodecOptions) {
+		o.storage = storage
+	}
+}
+
+// WithBSONMapCodecOptions sets the options of the map codec that
+// Codec.BuildBSONRegistry registers, which replaces the builder's map
+// codec. It has no effect on the package-level BuildBSONRegistry.
+func WithBSONMapCodecOptions(opts ...*bsonoptions.MapCodecOptions) BSONCodecOption {
+	return func(o *bsonCodecOptions) {
+		o.mapOptions = append(o.mapOptions, opts...)
+	}
+}
+
+func newBSONCodecOptions(opts []BSONCodecOption) bsonCodecOptions {
+	var result bsonCodecOptions
+	for _, opt := range opts {
+		opt(&result)
+	}
+
+	return result
+}
+
+// NewBSONCodec creates a BSONSemanticIDCodec that uses the package-level
+// settings.
+func NewBSONCodec(opts ...BSONCodecOption) *BSONSemanticIDCodec {
+	o := newBSONCodecOptions(opts)
+	return &BSONSemanticIDCodec{storage: o.storage}
+}
+
+// NewBSONPointerCodec creates a BSONSemanticIDPointerCodec that uses the
+// package-level settings.
+func NewBSONPointerCodec(opts ...BSONCodecOption) *BSONSemanticIDPointerCodec {
+	o := newBSONCodecOptions(opts)
+	return &BSONSemanticIDPointerCodec{storage: o.storage}
+}
+
+// BSONCodec returns a BSONSemanticIDCodec that uses the Codec's settings.
+func (c *Codec) BSONCodec(opts ...BSONCodecOption) *BSONSemanticIDCodec {
+	o := newBSONCodecOptions(opts)
+	return &BSONSemanticIDCodec{codec: c, storage: o.storage}
+}
+
+// BSONPointerCodec returns a BSONSemanticIDPointerCodec that uses the
+// Codec's settings.
+func (c *Codec) BSONPointerCodec(opts ...BSONCodecOption) *BSONSemanticIDPointerCodec {
+	o := newBSONCodecOptions(opts)
+	return &BSONSemanticIDPointerCodec{codec: c, storage: o.storage}
+}
+
+func bsonCodecOrDefault(c *Codec) *Codec {
+	if c == nil {
+		return defaultCodec()
+	}
+
+	return c
+}
 
 var _ bsoncodec.ValueEncoder = &BSONSemanticIDCodec{}
 var _ bsoncodec.ValueDecoder = &BSONSemanticIDCodec{}
@@ -26,13 +135,472 @@ var _ bsoncodec.ValueDecoder = &BSONSemanticIDCodec{}
 var _ bsoncodec.ValueEncoder = &BSONSemanticIDPointerCodec{}
 var _ bsoncodec.ValueDecoder = &BSONSemanticIDPointerCodec{}
 
+var _ bsoncodec.CodecZeroer = &BSONSemanticIDCodec{}
+var _ bsoncodec.CodecZeroer = &BSONSemanticIDPointerCodec{}
+
+var _ bsoncodec.KeyMarshaler = &SemanticID{}
+var _ bsoncodec.KeyUnmarshaler = &SemanticID{}
+
+// BuildBSONRegistry registers the SemanticID codecs for values and
+// pointers on the given registry builder and returns the resulting
+// registry, ready to be passed to the mongo client options. Typed IDs
+// are stored using the same storage as SemanticIDs. Slices and
+// maps of SemanticIDs are handled by the builder's default codecs, which
+// encode map keys through MarshalKey and UnmarshalKey. If rb is nil, a
+// builder with the default bson codecs is used.
+func BuildBSONRegistry(rb *bsoncodec.RegistryBuilder, opts ...BSONCodecOption) *bsoncodec.Registry {
+	return registerBSONCodecs(rb, NewBSONCodec(opts...), NewBSONPointerCodec(opts...), nil)
+}
+
+// BuildBSONRegistry works like the package-level BuildBSONRegistry, but
+// registers codecs that use the Codec's settings. Since MarshalKey and
+// UnmarshalKey always use the package-level settings, this also
+// registers a map codec that encodes SemanticID keys using the Codec's
+// settings. It replaces the builder's map codec for all maps, so map
+// codec options have to be passed with WithBSONMapCodecOptions.
+func (c *Codec) BuildBSONRegistry(rb *bsoncodec.RegistryBuilder, opts ...BSONCodecOption) *bsoncodec.Registry {
+	o := newBSONCodecOptions(opts)
+	mapCodec := &bsonMapCodec{codec: c, inner: bsoncodec.NewMapCodec(o.mapOptions...)}
+	return registerBSONCodecs(rb, c.BSONCodec(opts...), c.BSONPointerCodec(opts...), mapCodec)
+}
+
+func registerBSONCodecs(
+	rb *bsoncodec.RegistryBuilder,
+	valueCodec *BSONSemanticIDCodec,
+	pointerCodec *BSONSemanticIDPointerCodec,
+	mapCodec *bsonMapCodec,
+) *bsoncodec.Registry {
+	if rb == nil {
+		rb = bson.NewRegistryBuilder()
+	}
+
+	idCodec := &bsonIDCodec{codec: valueCodec.codec, storage: valueCodec.storage}
+	rb.
+		RegisterCodec(rawType, valueCodec).
+		RegisterCodec(pointerType, pointerCodec).
+		RegisterHookEncoder(bsonIDValueType, idCodec).
+		RegisterHookDecoder(bsonIDPointerType, idCodec).
+		RegisterHookEncoder(valueMarshalerType, idCodec).
+		RegisterHookDecoder(valueUnmarshalerType, idCodec)
+
+	if mapCodec != nil {
+		rb.
+			RegisterDefaultEncoder(reflect.Map, mapCodec).
+			RegisterDefaultDecoder(reflect.Map, mapCodec)
+	}
+
+	return rb.Build()
+}
+
+var (
+	bsonIDValueType      = reflect.TypeOf((*bsonIDValue)(nil)).Elem()
+	bsonIDPointerType    = reflect.TypeOf((*bsonIDPointer)(nil)).Elem()
+	valueMarshalerType   = reflect.TypeOf((*bsoncodec.ValueMarshaler)(nil)).Elem()
+	valueUnmarshalerType = reflect.TypeOf((*bsoncodec.ValueUnmarshaler)(nil)).Elem()
+)
+
+// bsonIDValue and bsonIDPointer are implemented by typed IDs, so that
+// the BSON codecs can store them like SemanticIDs.
+type bsonIDValue interface {
+	encodeBSONID(c *Codec, vw bsonrw.ValueWriter, storage BSONStorage) error
+}
+
+type bsonIDPointer interface {
+	decodeBSONID(c *Codec, vr bsonrw.ValueReader) error
+}
+
+// bsonIDCodec encodes and decodes typed IDs using the storage of the
+// SemanticID codecs. Since typed IDs also implement the ValueMarshaler
+// interfaces, whose hooks the registry checks first, it replaces those
+// hooks as well and hands all other types to the default hooks.
+type bsonIDCodec struct {
+	codec   *Codec
+	storage BSONStorage
+}
+
+var _ bsoncodec.ValueCodec = &bsonIDCodec{}
+
+func (ic *bsonIDCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || !val.Type().Implements(bsonIDValueType) {
+		return bsoncodec.DefaultValueEncoders{}.ValueMarshalerEncodeValue(ec, vw, val)
+	}
+
+	if val.Kind() == reflect.Ptr && val.IsNil() {
+		return vw.WriteNull()
+	}
+
+	id := val.Interface().(bsonIDValue)
+	return id.encodeBSONID(bsonCodecOrDefault(ic.codec), vw, ic.storage)
+}
+
+func (ic *bsonIDCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	c := bsonCodecOrDefault(ic.codec)
+
+	if val.Kind() == reflect.Ptr && val.CanSet() && val.Type().Implements(bsonIDPointerType) {
+		switch vr.Type() {
+		case bsontype.Null:
+			val.Set(reflect.Zero(val.Type()))
+			return vr.ReadNull()
+		case bsontype.Undefined:
+			val.Set(reflect.Zero(val.Type()))
+			return vr.ReadUndefined()
+		}
+
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+
+		return val.Interface().(bsonIDPointer).decodeBSONID(c, vr)
+	}
+
+	if val.IsValid() && val.CanAddr() && reflect.PtrTo(val.Type()).Implements(bsonIDPointerType) {
+		return val.Addr().Interface().(bsonIDPointer).decodeBSONID(c, vr)
+	}
+
+	return bsoncodec.DefaultValueDecoders{}.ValueUnmarshalerDecodeValue(dc, vr, val)
+}
+
+var stringType = reflect.TypeOf("")
+
+// bsonMapCodec encodes and decodes maps with SemanticID keys using the
+// Codec's settings, by converting them from and to maps with string
+// keys. All other maps are handled by the inner map codec.
+type bsonMapCodec struct {
+	codec *Codec
+	inner *bsoncodec.MapCodec
+}
+
+var _ bsoncodec.ValueCodec = &bsonMapCodec{}
+
+func (mc *bsonMapCodec) EncodeValue(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || val.Kind() != reflect.Map || val.Type().Key() != rawType || val.IsNil() {
+		return mc.inner.EncodeValue(ec, vw, val)
+	}
+
+	converted := reflect.MakeMapWithSize(reflect.MapOf(stringType, val.Type().Elem()), val.Len())
+	iter := val.MapRange()
+	for iter.Next() {
+		key := mc.codec.encodeKey(iter.Key().Interface().(SemanticID))
+		converted.SetMapIndex(reflect.ValueOf(key), iter.Value())
+	}
+
+	return mc.inner.EncodeValue(ec, vw, converted)
+}
+
+func (mc *bsonMapCodec) DecodeValue(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if val.Kind() != reflect.Map || val.Type().Key() != rawType || (!val.CanSet() && val.IsNil()) {
+		return mc.inner.DecodeValue(dc, vr, val)
+	}
+
+	converted := reflect.New(reflect.MapOf(stringType, val.Type().Elem())).Elem()
+	if err := mc.inner.DecodeValue(dc, vr, converted); err != nil {
+		return err
+	}
+
+	if converted.IsNil() {
+		if val.CanSet() {
+			val.Set(reflect.Zero(val.Type()))
+		}
+
+		return nil
+	}
+
+	if val.IsNil() {
+		val.Set(reflect.MakeMapWithSize(val.Type(), converted.Len()))
+	}
+
+	iter := converted.MapRange()
+	for iter.Next() {
+		sid, err := mc.codec.decodeKey(iter.Key().String())
+		if err != nil {
+			return err
+		}
+
+		val.SetMapIndex(reflect.ValueOf(sid), iter.Value())
+	}
+
+	return nil
+}
+
+// MarshalKey implements the bsoncodec.KeyMarshaler interface, which
+// allows SemanticIDs to be used as keys in maps encoded to BSON. Nil
+// SemanticIDs are encoded as empty keys.
+func (sid SemanticID) MarshalKey() (string, error) {
+	return defaultCodec().encodeKey(sid), nil
+}
+
+// UnmarshalKey implements the bsoncodec.KeyUnmarshaler interface. Empty
+// keys are decoded into nil SemanticIDs.
+func (sid *SemanticID) UnmarshalKey(key string) error {
+	parsed, err := defaultCodec().decodeKey(key)
+	if err != nil {
+		return err
+	}
+
+	*sid = parsed
+	return nil
+}
+
+// encodeKey returns the map key for the given SemanticID.
+func (c *Codec) encodeKey(sid SemanticID) string {
+	if sid.IsNil() {
+		return ""
+	}
+
+	return c.String(sid)
+}
+
+// decodeKey parses a map key written by encodeKey.
+func (c *Codec) decodeKey(key string) (SemanticID, error) {
+	if key == "" {
+		return empty, nil
+	}
+
+	return c.FromString(key)
+}
+
+// BSONTimeRangeFilter returns a filter that matches all SemanticIDs with
+// the given namespace and collection that were created between from and
+// to, inclusively. The boundaries are SemanticID values, so the registry
+// used for the query needs to include the SemanticID codecs. The ID
+// provider has to implement StringTimeOrderer and report that its IDs
+// sort by time as strings, which excludes decimal snowflake IDs.
+func BSONTimeRangeFilter(namespace, collection string, from, to time.Time) (bson.M, error) {
+	return defaultCodec().BSONTimeRangeFilter(namespace, collection, from, to)
+}
+
+// BSONTimeRangeFilter returns a filter that matches all SemanticIDs
+// created between from and to, using the ID provider selected by
+// the Codec.
+func (c *Codec) BSONTimeRangeFilter(namespace, collection string, from, to time.Time) (bson.M, error) {
+	idp := c.providerFor(namespace, collection)
+	if sto, ok := idp.(StringTimeOrderer); !ok || !sto.StringsSortByTime() {
+		return nil, &SemanticIDError{
+			errCode: errNoTimestamp,
+			message: fmt.Sprintf("IDs of provider %T don't sort by time as strings", idp),
+		}
+	}
+
+	min, err := c.MinForTime(namespace, collection, from)
+	if err != nil {
+		return nil, err
+	}
+
+	max, err := c.MaxForTime(namespace, collection, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{"$gte": min, "$lte": max}, nil
+}
+
+// writeBSONNil writes a nil SemanticID according to the NilPolicy.
+func (c *Codec) writeBSONNil(vw bsonrw.ValueWriter) error {
+	if c.nilAsEmptyString() {
+		return vw.WriteString("")
+	}
+
+	return vw.WriteNull()
+}
+
+// writeBSON writes the given SemanticID using the selected storage.
+func (c *Codec) writeBSON(vw bsonrw.ValueWriter, sid SemanticID, storage BSONStorage) error {
+	switch storage {
+	case BSONStorageString:
+		return vw.WriteString(c.String(sid))
+	case BSONStorageDocument:
+		return c.writeBSONDocument(vw, sid)
+	case BSONStorageBinary:
+		b, err := c.EncodeBinary(sid)
+		if err != nil {
+			return err
+		}
+
+		return vw.WriteBinary(b)
+	}
+
+	return fmt.Errorf("Unknown BSON storage %d", storage)
+}
+
+// writeBSONDocument writes the given SemanticID as a subdocument, as
+// described for BSONStorageDocument.
+func (c *Codec) writeBSONDocument(vw bsonrw.ValueWriter, sid SemanticID) error {
+	id, ok, err := c.idToBytes(sid)
+	if err != nil {
+		return err
+	}
+
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+
+	ew, err := dw.WriteDocumentElement(bsonNamespaceField)
+	if err != nil {
+		return err
+	}
+
+	if err := ew.WriteString(sid.Namespace); err != nil {
+		return err
+	}
+
+	ew, err = dw.WriteDocumentElement(bsonCollectionField)
+	if err != nil {
+		return err
+	}
+
+	if err := ew.WriteString(sid.Collection); err != nil {
+		return err
+	}
+
+	ew, err = dw.WriteDocumentElement(bsonIDField)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		err = ew.WriteBinary(id)
+	} else {
+		err = ew.WriteString(sid.ID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return dw.WriteDocumentEnd()
+}
+
+// marshalBSONValue encodes the given SemanticID as a single BSON string
+// value, for types implementing bsoncodec.ValueMarshaler.
+func (c *Codec) marshalBSONValue(sid SemanticID) (bsontype.Type, []byte, error) {
+	if sid.IsNil() && !c.nilAsEmptyString() {
+		return bsontype.Null, nil, nil
+	}
+
+	return bsontype.String, bsoncore.AppendString(nil, c.String(sid)), nil
+}
+
+// unmarshalBSONValue decodes a single BSON value in any of the
+// supported forms, for types implementing bsoncodec.ValueUnmarshaler.
+func (c *Codec) unmarshalBSONValue(t bsontype.Type, data []byte) (SemanticID, error) {
+	if t == bsontype.Null || t == bsontype.Undefined {
+		return empty, nil
+	}
+
+	return c.readBSON(bsonrw.NewBSONValueReader(t, data))
+}
+
+// readBSON reads a SemanticID stored in any of the supported forms.
+func (c *Codec) readBSON(vr bsonrw.ValueReader) (SemanticID, error) {
+	switch vr.Type() {
+	case bsontype.String:
+		str, err := vr.ReadString()
+		if err != nil {
+			return empty, err
+		}
+
+		if str == "" {
+			return empty, nil
+		}
+
+		return c.FromString(str)
+	case bsontype.Binary:
+		b, _, err := vr.ReadBinary()
+		if err != nil {
+			return empty, err
+		}
+
+		var result SemanticID
+		err = c.DecodeBinary(b, &result)
+		return result, err
+	case bsontype.EmbeddedDocument:
+		return c.readBSONDocument(vr)
+	default:
+		return empty, fmt.Errorf("cannot decode %v into a semanticid", vr.Type())
+	}
+}
+
+func (c *Codec) readBSONDocument(vr bsonrw.ValueReader) (SemanticID, error) {
+	dr, err := vr.ReadDocument()
+	if err != nil {
+		return empty, err
+	}
+
+	var namespace, collection, id string
+	var binaryID []byte
+	isBinary := false
+
+	for {
+		key, evr, err := dr.ReadElement()
+		if err == bsonrw.ErrEOD {
+			break
+		}
+
+		if err != nil {
+			return empty, err
+		}
+
+		switch key {
+		case bsonNamespaceField:
+			namespace, err = evr.ReadString()
+		case bsonCollectionField:
+			collection, err = evr.ReadString()
+		case bsonIDField:
+			if evr.Type() == bsontype.Binary {
+				binaryID, _, err = evr.ReadBinary()
+				isBinary = true
+			} else {
+				id, err = evr.ReadString()
+			}
+		default:
+			err = evr.Skip()
+		}
+
+		if err != nil {
+			return empty, err
+		}
+	}
+
+	if namespace == "" && collection == "" && id == "" && !isBinary {
+		return empty, &SemanticIDError{errEmpty, "The given document was empty"}
+	}
+
+	parsed, err := c.newFromParts(namespace, collection, "")
+	if err != nil {
+		return empty, err
+	}
+
+	if isBinary {
+		id, err = c.idFromBytes(namespace, collection, binaryID)
+		if err != nil {
+			return empty, err
+		}
+	}
+
+	parsed.ID = id
+	parsed.ID, err = checkID(c.providerFor(namespace, collection), id, c.String(parsed))
+	if err != nil {
+		return empty, err
+	}
+
+	return parsed, nil
+}
+
+// IsTypeZero implements the CodecZeroer interface. Nil SemanticIDs in
+// fields tagged with `omitempty` are only omitted with NilOmit.
+func (bc *BSONSemanticIDCodec) IsTypeZero(v interface{}) bool {
+	sid, ok := v.(SemanticID)
+	return ok && sid.IsNil() && bsonCodecOrDefault(bc.codec).omitsNil()
+}
+
 // EncodeValue implements the ValueEncoder interface.
-func (*BSONSemanticIDCodec) EncodeValue(
+func (bc *BSONSemanticIDCodec) EncodeValue(
 	ec bsoncodec.EncodeContext,
 	vw bsonrw.ValueWriter,
 	val reflect.Value,
 ) error {
-	if val.Type() != rawType {
+	if !val.IsValid() || val.Type() != rawType {
 		return bsoncodec.ValueEncoderError{
 			Name:     "SemanticIDEncodeValue",
 			Types:    []reflect.Type{rawType},
@@ -40,20 +608,26 @@ func (*BSONSemanticIDCodec) EncodeValue(
 		}
 	}
 
-	isNilMethod := val.MethodByName("IsNil")
-	isNilResult := isNilMethod.Call([]reflect.Value{})[0].Bool()
-	if isNilResult {
-		return vw.WriteNull()
+	// Going through a pointer avoids copying the SemanticID into an
+	// interface, which would allocate.
+	var sid *SemanticID
+	if val.CanAddr() {
+		sid = val.Addr().Interface().(*SemanticID)
+	} else {
+		value := val.Interface().(SemanticID)
+		sid = &value
 	}
 
-	strMethod := val.MethodByName("String")
-	strResult := strMethod.Call([]reflect.Value{})[0].String()
+	c := bsonCodecOrDefault(bc.codec)
+	if sid.IsNil() {
+		return c.writeBSONNil(vw)
+	}
 
-	return vw.WriteString(strResult)
+	return c.writeBSON(vw, *sid, bc.storage)
 }
 
 // DecodeValue implements the ValueDecoder interface.
-func (*BSONSemanticIDCodec) DecodeValue(
+func (bc *BSONSemanticIDCodec) DecodeValue(
 	dc bsoncodec.DecodeContext,
 	vr bsonrw.ValueReader,
 	val reflect.Value,
@@ -66,44 +640,50 @@ func (*BSONSemanticIDCodec) DecodeValue(
 		}
 	}
 
-	if vr.Type() == bsontype.Null || vr.Type() == bsontype.Undefined {
-		val.Set(reflect.ValueOf(SemanticID{}))
-		_ = vr.ReadNull()
-		return nil
-	}
+	// Settable values are always addressable.
+	sid := val.Addr().Interface().(*SemanticID)
 
-	if vr.Type() != bsontype.String {
-		return fmt.Errorf("cannot decode %v into a semanticid", vr.Type())
+	switch vr.Type() {
+	case bsontype.Null:
+		*sid = SemanticID{}
+		return vr.ReadNull()
+	case bsontype.Undefined:
+		*sid = SemanticID{}
+		return vr.ReadUndefined()
 	}
 
-	str, err := vr.ReadString()
+	parsed, err := bsonCodecOrDefault(bc.codec).readBSON(vr)
 	if err != nil {
 		return err
 	}
 
-	parsed, err := FromString(str)
-	if err != nil {
-		return err
+	*sid = parsed
+	return nil
+}
+
+// IsTypeZero implements the CodecZeroer interface. Nil pointers are
+// always considered zero, while pointers to nil SemanticIDs are only
+// considered zero with NilOmit.
+func (bc *BSONSemanticIDPointerCodec) IsTypeZero(v interface{}) bool {
+	sid, ok := v.(*SemanticID)
+	if !ok {
+		return false
 	}
 
-	val.FieldByName("Namespace").SetString(parsed.Namespace)
-	val.FieldByName("Collection").SetString(parsed.Collection)
-	val.FieldByName("ID").SetString(parsed.ID)
+	if sid == nil {
+		return true
+	}
 
-	return nil
+	return sid.IsNil() && bsonCodecOrDefault(bc.codec).omitsNil()
 }
 
 // EncodeValue implements the ValueEncoder interface.
-func (*BSONSemanticIDPointerCodec) EncodeValue(
+func (bc *BSONSemanticIDPointerCodec) EncodeValue(
 	ec bsoncodec.EncodeContext,
 	vw bsonrw.ValueWriter,
 	val reflect.Value,
 ) error {
-	if val.IsNil() {
-		return vw.WriteNull()
-	}
-
-	if val.Type() != pointerType {
+	if !val.IsValid() || val.Type() != pointerType {
 		return bsoncodec.ValueEncoderError{
 			Name:     "SemanticIDEncodeValue",
 			Types:    []reflect.Type{pointerType},
@@ -111,20 +691,21 @@ func (*BSONSemanticIDPointerCodec) EncodeValue(
 		}
 	}
 
-	isNilMethod := val.MethodByName("IsNil")
-	isNilResult := isNilMethod.Call([]reflect.Value{})[0].Bool()
-	if isNilResult {
-		return vw.WriteString("")
+	sid := val.Interface().(*SemanticID)
+	if sid == nil {
+		return vw.WriteNull()
 	}
 
-	strMethod := val.MethodByName("String")
-	strResult := strMethod.Call([]reflect.Value{})[0].String()
+	c := bsonCodecOrDefault(bc.codec)
+	if sid.IsNil() {
+		return c.writeBSONNil(vw)
+	}
 
-	return vw.WriteString(strResult)
+	return c.writeBSON(vw, *sid, bc.storage)
 }
 
 // DecodeValue implements the ValueDecoder interface.
-func (*BSONSemanticIDPointerCodec) DecodeValue(
+func (bc *BSONSemanticIDPointerCodec) DecodeValue(
 	dc bsoncodec.DecodeContext,
 	vr bsonrw.ValueReader,
 	val reflect.Value,
@@ -137,36 +718,32 @@ func (*BSONSemanticIDPointerCodec) DecodeValue(
 		}
 	}
 
-	if vr.Type() == bsontype.Null || vr.Type() == bsontype.Undefined {
-		nilValue := reflect.Zero(reflect.TypeOf(&SemanticID{}))
-		val.Set(nilValue)
-		_ = vr.ReadNull()
-		return nil
-	}
+	// Settable values are always addressable.
+	target := val.Addr().Interface().(**SemanticID)
 
-	if vr.Type() != bsontype.String {
-		return fmt.Errorf("cannot decode %v into a semanticid", vr.Type())
+	switch vr.Type() {
+	case bsontype.Null:
+		*target = nil
+		return vr.ReadNull()
+	case bsontype.Undefined:
+		*target = nil
+		return vr.ReadUndefined()
 	}
 
-	str, err := vr.ReadString()
+	parsed, err := bsonCodecOrDefault(bc.codec).readBSON(vr)
 	if err != nil {
 		return err
 	}
 
-	parsed, err := FromString(str)
-	if err != nil {
-		return err
+	// Empty strings and bytes are nil forms as well, so they decode to
+	// a nil pointer just like null.
+	if parsed.IsNil() {
+		*target = nil
+		return nil
 	}
 
-	// NOTE: We need to set this to an empty value, in case
-	// the pointer is null. (We're gonna overwrite the existing
-	// value anyways, if there is one.)
-	val.Set(reflect.ValueOf(&SemanticID{}))
-	el := val.Elem()
-
-	el.FieldByName("Namespace").SetString(parsed.Namespace)
-	el.FieldByName("Collection").SetString(parsed.Collection)
-	el.FieldByName("ID").SetString(parsed.ID)
-
+	// NOTE: We always allocate a new SemanticID instead of writing
+	// to the existing one, since it might be shared with other values.
+	*target = &parsed
 	return nil
 }
@@ -2,15 +2,39 @@ package semanticid_test
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"strings"
+	"testing"
+	"time"
 
 	"github.com/happenslol/semanticid"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonoptions"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 )
 
+// prefixedValue is a bsoncodec.ValueMarshaler that isn't a typed ID.
+type prefixedValue string
+
+func (v prefixedValue) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue("prefixed:" + string(v))
+}
+
+func (v *prefixedValue) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	s, ok := bson.RawValue{Type: t, Value: data}.StringValueOK()
+	if !ok {
+		return fmt.Errorf("cannot decode %v into a prefixedValue", t)
+	}
+
+	*v = prefixedValue(strings.TrimPrefix(s, "prefixed:"))
+	return nil
+}
+
 var _ = Describe("bson", func() {
 	var (
 		reg            *bsoncodec.Registry
@@ -98,19 +122,402 @@ var _ = Describe("bson", func() {
 		})
 
 		Context("with a zero semanticid pointer", func() {
-			It("should return an empty err when unmarshalling", func() {
+			It("should be encoded like a zero semanticid value", func() {
 				val := bson.M{"id": zeroSIDPointer}
 				m, err := bson.MarshalWithRegistry(reg, val)
 				Expect(err).To(BeNil())
 
+				var raw map[string]interface{}
+				Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+				Expect(raw).To(HaveKeyWithValue("id", BeNil()))
+
 				var result map[string]*semanticid.SemanticID
 				err = bson.UnmarshalWithRegistry(reg, m, &result)
-				Expect(errors.Is(err, semanticid.ErrEmpty)).To(BeTrue())
+				Expect(err).To(BeNil())
 
 				Expect(result["id"]).To(BeNil())
 			})
 		})
 
+		Context("with a time range filter", func() {
+			It("should encode semanticid boundaries", func() {
+				from := time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+				to := from.Add(24 * time.Hour)
+
+				filter, err := semanticid.BSONTimeRangeFilter("shop", "orders", from, to)
+				Expect(err).To(BeNil())
+
+				m, err := bson.MarshalWithRegistry(reg, bson.M{"_id": filter})
+				Expect(err).To(BeNil())
+
+				var result struct {
+					ID struct {
+						Gte string `bson:"$gte"`
+						Lte string `bson:"$lte"`
+					} `bson:"_id"`
+				}
+				Expect(bson.Unmarshal(m, &result)).To(BeNil())
+
+				min := semanticid.Must(semanticid.MinForTime("shop", "orders", from))
+				max := semanticid.Must(semanticid.MaxForTime("shop", "orders", to))
+				Expect(result.ID.Gte).To(Equal(min.String()))
+				Expect(result.ID.Lte).To(Equal(max.String()))
+
+				inRange := semanticid.Must(semanticid.Builder().
+					WithNamespace("shop").
+					WithCollection("orders").
+					WithIDProvider(semanticid.NewULIDProvider(semanticid.WithULIDClock(func() time.Time {
+						return from.Add(time.Hour)
+					}))).
+					Build())
+				Expect(inRange.String() > result.ID.Gte).To(BeTrue())
+				Expect(inRange.String() < result.ID.Lte).To(BeTrue())
+			})
+
+			It("should reject providers whose IDs don't sort by time as strings", func() {
+				from := time.Date(2022, time.June, 1, 0, 0, 0, 0, time.UTC)
+				to := from.Add(24 * time.Hour)
+
+				decimal, err := semanticid.NewSnowflakeProvider(1)
+				Expect(err).To(BeNil())
+
+				codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(decimal))
+				_, err = codec.BSONTimeRangeFilter("shop", "orders", from, to)
+				Expect(errors.Is(err, semanticid.ErrNoTimestamp)).To(BeTrue())
+
+				base62, err := semanticid.NewSnowflakeProvider(1,
+					semanticid.WithSnowflakeEncoding(semanticid.SnowflakeBase62))
+				Expect(err).To(BeNil())
+
+				codec = semanticid.NewCodec(semanticid.WithDefaultIDProvider(base62))
+				_, err = codec.BSONTimeRangeFilter("shop", "orders", from, to)
+				Expect(err).To(BeNil())
+
+				// MinForTime doesn't depend on the string order
+				_, err = semanticid.NewCodec(semanticid.WithDefaultIDProvider(decimal)).MinForTime("shop", "orders", from)
+				Expect(err).To(BeNil())
+			})
+		})
+
+		Context("with document storage", func() {
+			var docReg *bsoncodec.Registry
+
+			BeforeEach(func() {
+				rb := bsoncodec.NewRegistryBuilder()
+				bsoncodec.DefaultValueDecoders{}.RegisterDefaultDecoders(rb)
+				bsoncodec.DefaultValueEncoders{}.RegisterDefaultEncoders(rb)
+
+				storage := semanticid.WithBSONStorage(semanticid.BSONStorageDocument)
+				rb.RegisterCodec(
+					reflect.TypeOf(&semanticid.SemanticID{}),
+					semanticid.NewBSONPointerCodec(storage),
+				)
+
+				rb.RegisterCodec(
+					reflect.TypeOf(semanticid.SemanticID{}),
+					semanticid.NewBSONCodec(storage),
+				)
+
+				docReg = rb.Build()
+			})
+
+			It("should store the ID part as binary", func() {
+				m, err := bson.MarshalWithRegistry(docReg, bson.M{"id": sidValue})
+				Expect(err).To(BeNil())
+
+				var raw struct {
+					ID struct {
+						Namespace  string `bson:"ns"`
+						Collection string `bson:"coll"`
+						ID         []byte `bson:"id"`
+					} `bson:"id"`
+				}
+				Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+				Expect(raw.ID.Namespace).To(Equal(sidValue.Namespace))
+				Expect(raw.ID.Collection).To(Equal(sidValue.Collection))
+				Expect(raw.ID.ID).To(HaveLen(16))
+			})
+
+			It("should work correctly with values and pointers", func() {
+				val := bson.M{"id": sidValue, "ptr": sidPointer}
+				m, err := bson.MarshalWithRegistry(docReg, val)
+				Expect(err).To(BeNil())
+
+				var result struct {
+					ID  semanticid.SemanticID  `bson:"id"`
+					Ptr *semanticid.SemanticID `bson:"ptr"`
+				}
+				Expect(bson.UnmarshalWithRegistry(docReg, m, &result)).To(BeNil())
+				Expect(result.ID).To(Equal(sidValue))
+				Expect(*result.Ptr).To(Equal(*sidPointer))
+			})
+
+			It("should store IDs without a binary form as strings", func() {
+				codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(&TestProvider{}))
+				sid := semanticid.Must(codec.NewDefault())
+
+				rb := bsoncodec.NewRegistryBuilder()
+				bsoncodec.DefaultValueDecoders{}.RegisterDefaultDecoders(rb)
+				bsoncodec.DefaultValueEncoders{}.RegisterDefaultEncoders(rb)
+				rb.RegisterCodec(
+					reflect.TypeOf(semanticid.SemanticID{}),
+					codec.BSONCodec(semanticid.WithBSONStorage(semanticid.BSONStorageDocument)),
+				)
+				testReg := rb.Build()
+
+				m, err := bson.MarshalWithRegistry(testReg, bson.M{"id": sid})
+				Expect(err).To(BeNil())
+
+				var raw struct {
+					ID struct {
+						ID string `bson:"id"`
+					} `bson:"id"`
+				}
+				Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+				Expect(raw.ID.ID).To(Equal("1234"))
+
+				var result map[string]semanticid.SemanticID
+				Expect(bson.UnmarshalWithRegistry(testReg, m, &result)).To(BeNil())
+				Expect(result["id"]).To(Equal(sid))
+			})
+
+			It("should read both forms", func() {
+				stringForm, err := bson.MarshalWithRegistry(reg, bson.M{"id": sidValue})
+				Expect(err).To(BeNil())
+
+				docForm, err := bson.MarshalWithRegistry(docReg, bson.M{"id": sidValue})
+				Expect(err).To(BeNil())
+
+				for _, r := range []*bsoncodec.Registry{reg, docReg} {
+					for _, m := range [][]byte{stringForm, docForm} {
+						var result map[string]*semanticid.SemanticID
+						Expect(bson.UnmarshalWithRegistry(r, m, &result)).To(BeNil())
+						Expect(*result["id"]).To(Equal(sidValue))
+					}
+				}
+			})
+
+			It("should reject invalid documents", func() {
+				m, err := bson.Marshal(bson.M{"id": bson.M{"ns": "a", "coll": "b", "id": []byte{1, 2, 3}}})
+				Expect(err).To(BeNil())
+
+				var result map[string]semanticid.SemanticID
+				err = bson.UnmarshalWithRegistry(docReg, m, &result)
+				Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+
+				m, err = bson.Marshal(bson.M{"id": bson.M{}})
+				Expect(err).To(BeNil())
+
+				err = bson.UnmarshalWithRegistry(docReg, m, &result)
+				Expect(errors.Is(err, semanticid.ErrEmpty)).To(BeTrue())
+			})
+		})
+
+		Context("with binary storage", func() {
+			It("should be smaller than the string form and read all forms", func() {
+				rb := bsoncodec.NewRegistryBuilder()
+				bsoncodec.DefaultValueDecoders{}.RegisterDefaultDecoders(rb)
+				bsoncodec.DefaultValueEncoders{}.RegisterDefaultEncoders(rb)
+
+				storage := semanticid.WithBSONStorage(semanticid.BSONStorageBinary)
+				rb.RegisterCodec(
+					reflect.TypeOf(&semanticid.SemanticID{}),
+					semanticid.NewBSONPointerCodec(storage),
+				)
+
+				rb.RegisterCodec(
+					reflect.TypeOf(semanticid.SemanticID{}),
+					semanticid.NewBSONCodec(storage),
+				)
+
+				binReg := rb.Build()
+
+				m, err := bson.MarshalWithRegistry(binReg, bson.M{"id": sidValue})
+				Expect(err).To(BeNil())
+
+				var raw struct {
+					ID []byte `bson:"id"`
+				}
+				Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+				expected, err := sidValue.MarshalBinary()
+				Expect(err).To(BeNil())
+				Expect(raw.ID).To(Equal(expected))
+
+				stringForm, err := bson.MarshalWithRegistry(reg, bson.M{"id": sidValue})
+				Expect(err).To(BeNil())
+				Expect(len(m)).To(BeNumerically("<", len(stringForm)))
+
+				for _, form := range [][]byte{m, stringForm} {
+					var result map[string]*semanticid.SemanticID
+					Expect(bson.UnmarshalWithRegistry(binReg, form, &result)).To(BeNil())
+					Expect(*result["id"]).To(Equal(sidValue))
+				}
+
+				var result map[string]semanticid.SemanticID
+				Expect(bson.UnmarshalWithRegistry(reg, m, &result)).To(BeNil())
+				Expect(result["id"]).To(Equal(sidValue))
+			})
+		})
+
+		Context("with a registry from the registry builder helper", func() {
+			It("should handle values, pointers, slices and map keys", func() {
+				helperReg := semanticid.BuildBSONRegistry(nil)
+
+				type model struct {
+					ID     semanticid.SemanticID            `bson:"_id"`
+					Ptr    *semanticid.SemanticID           `bson:"ptr"`
+					Refs   []semanticid.SemanticID          `bson:"refs"`
+					Ptrs   []*semanticid.SemanticID         `bson:"ptrs"`
+					Counts map[semanticid.SemanticID]int    `bson:"counts"`
+					Named  map[string]semanticid.SemanticID `bson:"named"`
+				}
+
+				original := model{
+					ID:     sidValue,
+					Ptr:    sidPointer,
+					Refs:   []semanticid.SemanticID{sidValue, *sidPointer},
+					Ptrs:   []*semanticid.SemanticID{sidPointer, nil},
+					Counts: map[semanticid.SemanticID]int{sidValue: 1, *sidPointer: 2},
+					Named:  map[string]semanticid.SemanticID{"a": sidValue},
+				}
+
+				m, err := bson.MarshalWithRegistry(helperReg, original)
+				Expect(err).To(BeNil())
+
+				var raw struct {
+					Refs   []string       `bson:"refs"`
+					Counts map[string]int `bson:"counts"`
+				}
+				Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+				Expect(raw.Refs).To(Equal([]string{sidValue.String(), sidPointer.String()}))
+				Expect(raw.Counts).To(HaveKeyWithValue(sidValue.String(), 1))
+
+				var result model
+				Expect(bson.UnmarshalWithRegistry(helperReg, m, &result)).To(BeNil())
+				Expect(result).To(Equal(original))
+			})
+
+			It("should encode map keys using the codec's settings", func() {
+				codec := semanticid.NewCodec(semanticid.WithSeparator(":"))
+				sid := semanticid.Must(codec.NewDefault())
+				counts := map[semanticid.SemanticID]int{sid: 1, {}: 2}
+
+				helperReg := codec.BuildBSONRegistry(nil)
+				m, err := bson.MarshalWithRegistry(helperReg, bson.M{"counts": counts})
+				Expect(err).To(BeNil())
+
+				var raw struct {
+					Counts map[string]int `bson:"counts"`
+				}
+				Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+				Expect(raw.Counts).To(Equal(map[string]int{codec.String(sid): 1, "": 2}))
+
+				var result struct {
+					Counts map[semanticid.SemanticID]int `bson:"counts"`
+				}
+				Expect(bson.UnmarshalWithRegistry(helperReg, m, &result)).To(BeNil())
+				Expect(result.Counts).To(Equal(counts))
+			})
+
+			It("should keep the builder's map codec", func() {
+				rb := bson.NewRegistryBuilder()
+				mapCodec := bsoncodec.NewMapCodec(bsonoptions.MapCodec().SetEncodeNilAsEmpty(true))
+				rb.RegisterDefaultEncoder(reflect.Map, mapCodec)
+
+				m, err := bson.MarshalWithRegistry(semanticid.BuildBSONRegistry(rb), bson.M{"m": map[string]int(nil)})
+				Expect(err).To(BeNil())
+				Expect(bson.Raw(m).Lookup("m").Type).To(Equal(bsontype.EmbeddedDocument))
+			})
+
+			It("should pass map codec options to the codec's map codec", func() {
+				codec := semanticid.NewCodec(semanticid.WithSeparator(":"))
+				reg := codec.BuildBSONRegistry(nil, semanticid.WithBSONMapCodecOptions(
+					bsonoptions.MapCodec().SetEncodeNilAsEmpty(true),
+				))
+
+				m, err := bson.MarshalWithRegistry(reg, bson.M{
+					"m":   map[string]int(nil),
+					"ids": map[semanticid.SemanticID]int(nil),
+				})
+				Expect(err).To(BeNil())
+				Expect(bson.Raw(m).Lookup("m").Type).To(Equal(bsontype.EmbeddedDocument))
+				Expect(bson.Raw(m).Lookup("ids").Type).To(Equal(bsontype.EmbeddedDocument))
+			})
+
+			It("should round-trip nil map keys without the registry helper", func() {
+				counts := map[semanticid.SemanticID]int{sidValue: 1, {}: 2}
+				m, err := bson.Marshal(bson.M{"counts": counts})
+				Expect(err).To(BeNil())
+
+				var result struct {
+					Counts map[semanticid.SemanticID]int `bson:"counts"`
+				}
+				Expect(bson.Unmarshal(m, &result)).To(BeNil())
+				Expect(result.Counts).To(Equal(counts))
+			})
+
+			It("should use the given builder, codec and storage", func() {
+				codec := semanticid.NewCodec(semanticid.WithSeparator(":"))
+				sid := semanticid.Must(codec.NewDefault())
+
+				rb := bsoncodec.NewRegistryBuilder()
+				bsoncodec.DefaultValueDecoders{}.RegisterDefaultDecoders(rb)
+				bsoncodec.DefaultValueEncoders{}.RegisterDefaultEncoders(rb)
+				helperReg := codec.BuildBSONRegistry(rb)
+
+				m, err := bson.MarshalWithRegistry(helperReg, bson.M{"id": sid})
+				Expect(err).To(BeNil())
+
+				var raw map[string]string
+				Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+				Expect(raw["id"]).To(Equal(codec.String(sid)))
+
+				binReg := semanticid.BuildBSONRegistry(nil, semanticid.WithBSONStorage(semanticid.BSONStorageBinary))
+				m, err = bson.MarshalWithRegistry(binReg, bson.M{"id": sidValue})
+				Expect(err).To(BeNil())
+
+				var rawBinary struct {
+					ID []byte `bson:"id"`
+				}
+				Expect(bson.Unmarshal(m, &rawBinary)).To(BeNil())
+				Expect(rawBinary.ID).NotTo(BeEmpty())
+			})
+
+			It("should reject unknown storage modes", func() {
+				reg := semanticid.BuildBSONRegistry(nil, semanticid.WithBSONStorage(semanticid.BSONStorage(42)))
+				_, err := bson.MarshalWithRegistry(reg, bson.M{"id": semanticid.Must(semanticid.NewDefault())})
+				Expect(err).To(MatchError(ContainSubstring("Unknown BSON storage 42")))
+			})
+
+			It("should leave other value marshalers to the default hooks", func() {
+				reg := semanticid.BuildBSONRegistry(nil, semanticid.WithBSONStorage(semanticid.BSONStorageBinary))
+				m, err := bson.MarshalWithRegistry(reg, bson.M{"v": prefixedValue("a")})
+				Expect(err).To(BeNil())
+				Expect(bson.Raw(m).Lookup("v").StringValue()).To(Equal("prefixed:a"))
+
+				var result struct {
+					V prefixedValue  `bson:"v"`
+					P *prefixedValue `bson:"p"`
+				}
+				m, err = bson.Marshal(bson.M{"v": "prefixed:a", "p": "prefixed:b"})
+				Expect(err).To(BeNil())
+				Expect(bson.UnmarshalWithRegistry(reg, m, &result)).To(BeNil())
+				Expect(result.V).To(Equal(prefixedValue("a")))
+				Expect(*result.P).To(Equal(prefixedValue("b")))
+			})
+
+			It("should reject invalid map keys", func() {
+				m, err := bson.Marshal(bson.M{"counts": bson.M{"invalid": 1}})
+				Expect(err).To(BeNil())
+
+				var result struct {
+					Counts map[semanticid.SemanticID]int `bson:"counts"`
+				}
+				err = bson.UnmarshalWithRegistry(semanticid.BuildBSONRegistry(nil), m, &result)
+				Expect(err).NotTo(BeNil())
+			})
+		})
+
 		Context("with a nil pointer", func() {
 			It("should always stay nil", func() {
 				val := bson.M{"id": (*semanticid.SemanticID)(nil)}
@@ -123,6 +530,201 @@ var _ = Describe("bson", func() {
 
 				Expect(result["id"]).To(BeNil())
 			})
+
+			It("should decode empty strings into nil pointers", func() {
+				m, err := bson.Marshal(bson.M{"id": ""})
+				Expect(err).To(BeNil())
+
+				existing := semanticid.Must(semanticid.NewDefault())
+				result := map[string]*semanticid.SemanticID{"id": &existing}
+				err = bson.UnmarshalWithRegistry(reg, m, &result)
+				Expect(err).To(BeNil())
+
+				Expect(result["id"]).To(BeNil())
+			})
 		})
 	})
 })
+
+type bsonBenchmarkDocument struct {
+	ID      semanticid.SemanticID  `bson:"_id"`
+	Owner   *semanticid.SemanticID `bson:"owner"`
+	Related semanticid.SemanticID  `bson:"related"`
+}
+
+func newBSONBenchmarkDocument() bsonBenchmarkDocument {
+	owner := semanticid.Must(semanticid.NewDefault())
+	return bsonBenchmarkDocument{
+		ID:      semanticid.Must(semanticid.NewDefault()),
+		Owner:   &owner,
+		Related: semanticid.Must(semanticid.NewDefault()),
+	}
+}
+
+func BenchmarkBSONEncode(b *testing.B) {
+	benchmarkBSONEncode(b, semanticid.BuildBSONRegistry(nil))
+}
+
+func BenchmarkBSONEncodeReflection(b *testing.B) {
+	benchmarkBSONEncode(b, reflectionBSONRegistry())
+}
+
+func BenchmarkBSONDecode(b *testing.B) {
+	benchmarkBSONDecode(b, semanticid.BuildBSONRegistry(nil))
+}
+
+func BenchmarkBSONDecodeReflection(b *testing.B) {
+	benchmarkBSONDecode(b, reflectionBSONRegistry())
+}
+
+func benchmarkBSONEncode(b *testing.B, reg *bsoncodec.Registry) {
+	doc := newBSONBenchmarkDocument()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := bson.MarshalWithRegistry(reg, doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkBSONDecode(b *testing.B, reg *bsoncodec.Registry) {
+	m, err := bson.MarshalWithRegistry(reg, newBSONBenchmarkDocument())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var result bsonBenchmarkDocument
+		if err := bson.UnmarshalWithRegistry(reg, m, &result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// reflectionBSONRegistry builds a registry with codecs that work like
+// the BSON codecs did before they stopped using reflection, so the
+// benchmarks can compare both implementations.
+func reflectionBSONRegistry() *bsoncodec.Registry {
+	rb := bson.NewRegistryBuilder()
+	rb.RegisterCodec(reflect.TypeOf(semanticid.SemanticID{}), reflectionBSONCodec{})
+	rb.RegisterCodec(reflect.TypeOf(&semanticid.SemanticID{}), reflectionBSONPointerCodec{})
+	return rb.Build()
+}
+
+type reflectionBSONCodec struct{}
+
+func (reflectionBSONCodec) EncodeValue(
+	ec bsoncodec.EncodeContext,
+	vw bsonrw.ValueWriter,
+	val reflect.Value,
+) error {
+	isNilMethod := val.MethodByName("IsNil")
+	isNilResult := isNilMethod.Call([]reflect.Value{})[0].Bool()
+	if isNilResult {
+		return vw.WriteNull()
+	}
+
+	sid := semanticid.SemanticID{
+		Namespace:  val.FieldByName("Namespace").String(),
+		Collection: val.FieldByName("Collection").String(),
+		ID:         val.FieldByName("ID").String(),
+	}
+
+	return vw.WriteString(sid.String())
+}
+
+func (reflectionBSONCodec) DecodeValue(
+	dc bsoncodec.DecodeContext,
+	vr bsonrw.ValueReader,
+	val reflect.Value,
+) error {
+	if vr.Type() == bsontype.Null || vr.Type() == bsontype.Undefined {
+		val.Set(reflect.ValueOf(semanticid.SemanticID{}))
+		return vr.ReadNull()
+	}
+
+	if vr.Type() != bsontype.String {
+		return fmt.Errorf("cannot decode %v into a semanticid", vr.Type())
+	}
+
+	str, err := vr.ReadString()
+	if err != nil {
+		return err
+	}
+
+	parsed, err := semanticid.FromString(str)
+	if err != nil {
+		return err
+	}
+
+	val.FieldByName("Namespace").SetString(parsed.Namespace)
+	val.FieldByName("Collection").SetString(parsed.Collection)
+	val.FieldByName("ID").SetString(parsed.ID)
+
+	return nil
+}
+
+type reflectionBSONPointerCodec struct{}
+
+func (reflectionBSONPointerCodec) EncodeValue(
+	ec bsoncodec.EncodeContext,
+	vw bsonrw.ValueWriter,
+	val reflect.Value,
+) error {
+	if val.IsNil() {
+		return vw.WriteNull()
+	}
+
+	isNilMethod := val.MethodByName("IsNil")
+	isNilResult := isNilMethod.Call([]reflect.Value{})[0].Bool()
+	if isNilResult {
+		return vw.WriteString("")
+	}
+
+	el := val.Elem()
+	sid := semanticid.SemanticID{
+		Namespace:  el.FieldByName("Namespace").String(),
+		Collection: el.FieldByName("Collection").String(),
+		ID:         el.FieldByName("ID").String(),
+	}
+
+	return vw.WriteString(sid.String())
+}
+
+func (reflectionBSONPointerCodec) DecodeValue(
+	dc bsoncodec.DecodeContext,
+	vr bsonrw.ValueReader,
+	val reflect.Value,
+) error {
+	if vr.Type() == bsontype.Null || vr.Type() == bsontype.Undefined {
+		val.Set(reflect.Zero(val.Type()))
+		return vr.ReadNull()
+	}
+
+	if vr.Type() != bsontype.String {
+		return fmt.Errorf("cannot decode %v into a semanticid", vr.Type())
+	}
+
+	str, err := vr.ReadString()
+	if err != nil {
+		return err
+	}
+
+	parsed, err := semanticid.FromString(str)
+	if err != nil {
+		return err
+	}
+
+	val.Set(reflect.ValueOf(&semanticid.SemanticID{}))
+	el := val.Elem()
+
+	el.FieldByName("Namespace").SetString(parsed.Namespace)
+	el.FieldByName("Collection").SetString(parsed.Collection)
+	el.FieldByName("ID").SetString(parsed.ID)
+
+	return nil
+}
@@ -1,21 +1,22 @@
 package semanticid
 
+import "github.com/gofrs/uuid"
+
 type SemanticIDBuilder struct {
+	codec      *Codec
 	namespace  string
 	collection string
 	idProvider IDProvider
 	from       string
 	validate   bool
+
+	derive       bool
+	keyNamespace uuid.UUID
+	key          string
 }
 
 func Builder() *SemanticIDBuilder {
-	return &SemanticIDBuilder{
-		namespace:  DefaultNamespace,
-		collection: DefaultCollection,
-		idProvider: DefaultIDProvider,
-		from:       "",
-		validate:   true,
-	}
+	return defaultCodec().Builder()
 }
 
 func (b *SemanticIDBuilder) WithNamespace(namespace string) *SemanticIDBuilder {
@@ -28,6 +29,9 @@ func (b *SemanticIDBuilder) WithCollection(collection string) *SemanticIDBuilder
 	return b
 }
 
+// WithIDProvider selects the provider that is used to generate or validate
+// the ID part. If no provider is selected, it is looked up in the
+// DefaultProviderRegistry, falling back to the DefaultIDProvider.
 func (b *SemanticIDBuilder) WithIDProvider(idp IDProvider) *SemanticIDBuilder {
 	b.idProvider = idp
 	return b
@@ -38,6 +42,17 @@ func (b *SemanticIDBuilder) FromString(s string) *SemanticIDBuilder {
 	return b
 }
 
+// DeriveFromKey makes the builder derive the ID part from the given
+// namespace and key instead of generating a random one, so that building
+// with the same key always results in the same SemanticID. This requires
+// an ID provider that implements IDDeriver, such as the UUIDProvider.
+func (b *SemanticIDBuilder) DeriveFromKey(namespace uuid.UUID, key string) *SemanticIDBuilder {
+	b.derive = true
+	b.keyNamespace = namespace
+	b.key = key
+	return b
+}
+
 func (b *SemanticIDBuilder) NoValidate() *SemanticIDBuilder {
 	b.validate = false
 	return b
@@ -45,8 +60,17 @@ func (b *SemanticIDBuilder) NoValidate() *SemanticIDBuilder {
 
 func (b *SemanticIDBuilder) Build() (SemanticID, error) {
 	if b.from != "" {
-		return fromStringWithParams(b.from, b.idProvider, b.validate)
-	} else {
-		return newWithParams(b.namespace, b.collection, b.idProvider)
+		return b.codec.fromStringWithParams(b.from, b.idProvider, b.validate)
 	}
+
+	idp := b.idProvider
+	if idp == nil {
+		idp = b.codec.providerFor(b.namespace, b.collection)
+	}
+
+	if b.derive {
+		return b.codec.newDerivedWithParams(b.namespace, b.collection, idp, b.keyNamespace, b.key)
+	}
+
+	return b.codec.newWithParams(b.namespace, b.collection, idp)
 }
@@ -1,6 +1,9 @@
 package semanticid_test
 
 import (
+	"errors"
+
+	"github.com/gofrs/uuid"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -77,6 +80,79 @@ var _ = Describe("builder", func() {
 			})
 		})
 
+		Context("to derive ids from keys", func() {
+			var uuidProvider semanticid.IDProvider
+
+			BeforeEach(func() {
+				uuidProvider = semanticid.NewUUIDProvider()
+			})
+
+			It("should always derive the same semanticid for the same key", func() {
+				first, err := semanticid.Builder().
+					WithIDProvider(uuidProvider).
+					WithCollection(testCollection).
+					DeriveFromKey(uuid.NamespaceURL, "https://example.com/users/1").
+					Build()
+				Expect(err).To(BeNil())
+
+				second, err := semanticid.Builder().
+					WithIDProvider(uuidProvider).
+					WithCollection(testCollection).
+					DeriveFromKey(uuid.NamespaceURL, "https://example.com/users/1").
+					Build()
+				Expect(err).To(BeNil())
+
+				Expect(first).To(Equal(second))
+				Expect(first.ID).To(Equal(uuid.NewV5(uuid.NamespaceURL, "https://example.com/users/1").String()))
+			})
+
+			It("should derive different semanticids for different keys", func() {
+				first, err := semanticid.Builder().
+					WithIDProvider(uuidProvider).
+					DeriveFromKey(uuid.NamespaceURL, "a").
+					Build()
+				Expect(err).To(BeNil())
+
+				second, err := semanticid.Builder().
+					WithIDProvider(uuidProvider).
+					DeriveFromKey(uuid.NamespaceURL, "b").
+					Build()
+				Expect(err).To(BeNil())
+
+				Expect(first.ID).NotTo(Equal(second.ID))
+			})
+
+			It("should derive ids that pass validation", func() {
+				sid, err := semanticid.Builder().
+					WithIDProvider(uuidProvider).
+					DeriveFromKey(uuid.NamespaceOID, "1.2.3").
+					Build()
+				Expect(err).To(BeNil())
+
+				_, err = semanticid.Builder().
+					WithIDProvider(uuidProvider).
+					FromString(sid.String()).
+					Build()
+				Expect(err).To(BeNil())
+			})
+
+			It("should reject derived ids that the provider doesn't accept", func() {
+				_, err := semanticid.Builder().
+					WithIDProvider(semanticid.NewUUIDProvider(semanticid.WithUUIDVersions(uuid.V4))).
+					DeriveFromKey(uuid.NamespaceURL, "a").
+					Build()
+				Expect(errors.Is(err, semanticid.ErrIDProvider)).To(BeTrue())
+			})
+
+			It("should fail for providers that can't derive ids", func() {
+				_, err := semanticid.Builder().
+					WithIDProvider(semanticid.NewULIDProvider()).
+					DeriveFromKey(uuid.NamespaceURL, "a").
+					Build()
+				Expect(errors.Is(err, semanticid.ErrIDProvider)).To(BeTrue())
+			})
+		})
+
 		Context("with custom values", func() {
 			It("should build the correct semanticid", func() {
 				sid, err := semanticid.Builder().
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/happenslol/semanticid"
+)
+
+// model describes a struct with an sid-tagged ID field.
+type model struct {
+	Name string
+	semanticid.Tag
+}
+
+// generate returns the source of the typed ID helpers for all models in
+// the package in dir, or nil if there are none. The file with the given
+// name is skipped, since it was generated by a previous run.
+func generate(dir, output string) ([]byte, error) {
+	pkg, models, err := parseModels(dir, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	err = fileTemplate.Execute(&buf, struct {
+		Package string
+		Models  []model
+	}{pkg, models})
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// parseModels returns the package name and all models declared in the
+// non-test go files in dir.
+func parseModels(dir, output string) (string, []model, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkg := ""
+	var models []model
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() ||
+			!strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") ||
+			name == output {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if pkg == "" {
+			pkg = file.Name.Name
+		}
+
+		fileModels, err := modelsInFile(fset, file)
+		if err != nil {
+			return "", nil, err
+		}
+
+		models = append(models, fileModels...)
+	}
+
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].Name < models[j].Name
+	})
+
+	return pkg, models, nil
+}
+
+func modelsInFile(fset *token.FileSet, file *ast.File) ([]model, error) {
+	var result []model
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || ts.TypeParams != nil {
+				continue
+			}
+
+			tag, pos, ok := idFieldTag(st)
+			if !ok {
+				continue
+			}
+
+			m, err := modelFromTag(ts.Name.Name, tag)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fset.Position(pos), err)
+			}
+
+			result = append(result, m)
+		}
+	}
+
+	return result, nil
+}
+
+// idFieldTag returns the sid tag of the struct's ID field.
+func idFieldTag(st *ast.StructType) (string, token.Pos, bool) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+
+		for _, name := range field.Names {
+			if name.Name != "ID" {
+				continue
+			}
+
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return "", token.NoPos, false
+			}
+
+			tag, ok := reflect.StructTag(raw).Lookup("sid")
+			return tag, field.Tag.Pos(), ok
+		}
+	}
+
+	return "", token.NoPos, false
+}
+
+func modelFromTag(name, tag string) (model, error) {
+	parsed, err := semanticid.ParseTag(tag)
+	if err != nil {
+		return model{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return model{Name: name, Tag: parsed}, nil
+}
+
+var fileTemplate = template.Must(template.New("sid").Parse(`// Code generated by sidgen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import "github.com/happenslol/semanticid"
+{{ range .Models }}
+// {{ .Name }}IDKind is the Kind of {{ .Name }} IDs.
+type {{ .Name }}IDKind struct{}
+
+// Namespace implements semanticid.Kind.
+func ({{ .Name }}IDKind) Namespace() string { return {{ printf "%q" .Namespace }} }
+
+// Collection implements semanticid.Kind.
+func ({{ .Name }}IDKind) Collection() string { return {{ printf "%q" .Collection }} }
+{{ if .Provider }}
+// Provider implements semanticid.KindProvider.
+func ({{ .Name }}IDKind) Provider() string { return {{ printf "%q" .Provider }} }
+{{ end }}
+// {{ .Name }}ID is the typed ID of {{ .Name }}.
+type {{ .Name }}ID = semanticid.ID[{{ .Name }}IDKind]
+
+// New{{ .Name }}ID creates a unique ID for {{ .Name }}.
+func New{{ .Name }}ID() ({{ .Name }}ID, error) {
+	return semanticid.NewID[{{ .Name }}IDKind]()
+}
+
+// Parse{{ .Name }}ID parses the given string into the typed ID of {{ .Name }}.
+func Parse{{ .Name }}ID(s string) ({{ .Name }}ID, error) {
+	return semanticid.ParseID[{{ .Name }}IDKind](s)
+}
+
+// Is{{ .Name }}ID reports whether the SemanticID belongs to {{ .Name }}.
+func Is{{ .Name }}ID(sid semanticid.SemanticID) bool {
+	_, err := semanticid.IDFrom[{{ .Name }}IDKind](sid)
+	return err == nil && !sid.IsNil()
+}
+{{ end }}`))
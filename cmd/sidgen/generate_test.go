@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+	"github.com/happenslol/semanticid/cmd/sidgen/internal/example"
+)
+
+var _ = Describe("sidgen", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "sidgen")
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(BeNil())
+	})
+
+	writeFile := func(name, src string) {
+		Expect(os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644)).To(BeNil())
+	}
+
+	Describe("Finding models", func() {
+		It("should use the sid tag of the ID field", func() {
+			writeFile("models.go", `package models
+
+type User struct {
+	ID    string `+"`sid:\"users\" json:\"id\"`"+`
+	Owner string `+"`sid:\"owners\"`"+`
+}
+
+type Untagged struct {
+	ID string
+}
+`)
+
+			pkg, models, err := parseModels(dir, "sid_gen.go")
+			Expect(err).To(BeNil())
+			Expect(pkg).To(Equal("models"))
+			Expect(models).To(Equal([]model{{Name: "User", Tag: semanticid.Tag{Collection: "users"}}}))
+		})
+
+		It("should skip tests and previously generated files", func() {
+			writeFile("models_test.go", "package models\n\ntype A struct {\n\tID string `sid:\"as\"`\n}\n")
+			writeFile("sid_gen.go", "package models\n\ntype B struct {\n\tID string `sid:\"bs\"`\n}\n")
+
+			src, err := generate(dir, "sid_gen.go")
+			Expect(err).To(BeNil())
+			Expect(src).To(BeNil())
+		})
+
+		It("should point to invalid tags", func() {
+			writeFile("models.go", "package models\n\ntype A struct {\n\tID string `sid:\"as,nss=b\"`\n}\n")
+
+			_, err := generate(dir, "sid_gen.go")
+			Expect(errors.Is(err, semanticid.ErrInvalidTag)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("models.go:4"))
+			Expect(err.Error()).To(ContainSubstring("unknown option `nss`"))
+		})
+
+		It("should read namespace and provider options", func() {
+			writeFile("models.go", "package models\n\ntype A struct {\n\tID string `sid:\"as,ns=b,provider=uuid\"`\n}\n")
+
+			_, models, err := parseModels(dir, "sid_gen.go")
+			Expect(err).To(BeNil())
+			Expect(models).To(Equal([]model{{
+				Name: "A",
+				Tag:  semanticid.Tag{Collection: "as", Namespace: "b", Provider: "uuid"},
+			}}))
+		})
+	})
+
+	Describe("Generating helpers", func() {
+		It("should be up to date for the example package", func() {
+			src, err := generate(filepath.Join("internal", "example"), "sid_gen.go")
+			Expect(err).To(BeNil())
+
+			existing, err := os.ReadFile(filepath.Join("internal", "example", "sid_gen.go"))
+			Expect(err).To(BeNil())
+			Expect(string(src)).To(Equal(string(existing)))
+		})
+
+		It("should create typed ids for each model", func() {
+			user, err := example.NewUserID()
+			Expect(err).To(BeNil())
+			Expect(user.SemanticID().Collection).To(Equal("users"))
+			Expect(example.IsUserID(user.SemanticID())).To(BeTrue())
+			Expect(example.IsOrderID(user.SemanticID())).To(BeFalse())
+			Expect(example.IsUserID(semanticid.SemanticID{})).To(BeFalse())
+
+			parsed, err := example.ParseUserID(user.String())
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(user))
+
+			_, err = example.ParseOrderID(user.String())
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+		})
+
+		It("should use the namespace and provider from the tag", func() {
+			invoice, err := example.NewInvoiceID()
+			Expect(err).To(BeNil())
+			Expect(invoice.SemanticID().Namespace).To(Equal("billing"))
+			Expect(semanticid.NewUUIDProvider().Validate(invoice.SemanticID().ID)).To(BeNil())
+
+			parsed, err := example.ParseInvoiceID(invoice.String())
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(invoice))
+		})
+	})
+})
@@ -0,0 +1,25 @@
+// Package example contains models used to test the code generated by
+// sidgen.
+package example
+
+import "github.com/happenslol/semanticid"
+
+//go:generate go run github.com/happenslol/semanticid/cmd/sidgen
+
+type User struct {
+	ID   semanticid.SemanticID `sid:"users"`
+	Name string
+}
+
+type Order struct {
+	ID    semanticid.SemanticID `sid:"orders" json:"id"`
+	Owner semanticid.SemanticID `sid:"users"`
+}
+
+type Invoice struct {
+	ID semanticid.SemanticID `sid:"invoices,ns=billing,provider=uuid"`
+}
+
+type Untagged struct {
+	ID semanticid.SemanticID
+}
@@ -0,0 +1,92 @@
+// Code generated by sidgen. DO NOT EDIT.
+
+package example
+
+import "github.com/happenslol/semanticid"
+
+// InvoiceIDKind is the Kind of Invoice IDs.
+type InvoiceIDKind struct{}
+
+// Namespace implements semanticid.Kind.
+func (InvoiceIDKind) Namespace() string { return "billing" }
+
+// Collection implements semanticid.Kind.
+func (InvoiceIDKind) Collection() string { return "invoices" }
+
+// Provider implements semanticid.KindProvider.
+func (InvoiceIDKind) Provider() string { return "uuid" }
+
+// InvoiceID is the typed ID of Invoice.
+type InvoiceID = semanticid.ID[InvoiceIDKind]
+
+// NewInvoiceID creates a unique ID for Invoice.
+func NewInvoiceID() (InvoiceID, error) {
+	return semanticid.NewID[InvoiceIDKind]()
+}
+
+// ParseInvoiceID parses the given string into the typed ID of Invoice.
+func ParseInvoiceID(s string) (InvoiceID, error) {
+	return semanticid.ParseID[InvoiceIDKind](s)
+}
+
+// IsInvoiceID reports whether the SemanticID belongs to Invoice.
+func IsInvoiceID(sid semanticid.SemanticID) bool {
+	_, err := semanticid.IDFrom[InvoiceIDKind](sid)
+	return err == nil && !sid.IsNil()
+}
+
+// OrderIDKind is the Kind of Order IDs.
+type OrderIDKind struct{}
+
+// Namespace implements semanticid.Kind.
+func (OrderIDKind) Namespace() string { return "" }
+
+// Collection implements semanticid.Kind.
+func (OrderIDKind) Collection() string { return "orders" }
+
+// OrderID is the typed ID of Order.
+type OrderID = semanticid.ID[OrderIDKind]
+
+// NewOrderID creates a unique ID for Order.
+func NewOrderID() (OrderID, error) {
+	return semanticid.NewID[OrderIDKind]()
+}
+
+// ParseOrderID parses the given string into the typed ID of Order.
+func ParseOrderID(s string) (OrderID, error) {
+	return semanticid.ParseID[OrderIDKind](s)
+}
+
+// IsOrderID reports whether the SemanticID belongs to Order.
+func IsOrderID(sid semanticid.SemanticID) bool {
+	_, err := semanticid.IDFrom[OrderIDKind](sid)
+	return err == nil && !sid.IsNil()
+}
+
+// UserIDKind is the Kind of User IDs.
+type UserIDKind struct{}
+
+// Namespace implements semanticid.Kind.
+func (UserIDKind) Namespace() string { return "" }
+
+// Collection implements semanticid.Kind.
+func (UserIDKind) Collection() string { return "users" }
+
+// UserID is the typed ID of User.
+type UserID = semanticid.ID[UserIDKind]
+
+// NewUserID creates a unique ID for User.
+func NewUserID() (UserID, error) {
+	return semanticid.NewID[UserIDKind]()
+}
+
+// ParseUserID parses the given string into the typed ID of User.
+func ParseUserID(s string) (UserID, error) {
+	return semanticid.ParseID[UserIDKind](s)
+}
+
+// IsUserID reports whether the SemanticID belongs to User.
+func IsUserID(sid semanticid.SemanticID) bool {
+	_, err := semanticid.IDFrom[UserIDKind](sid)
+	return err == nil && !sid.IsNil()
+}
@@ -0,0 +1,49 @@
+// Command sidgen generates typed ID helpers for models whose `ID` field
+// has an `sid` tag. It is meant to be used with go generate:
+//
+//	//go:generate go run github.com/happenslol/semanticid/cmd/sidgen
+//
+// For a model like
+//
+//	type User struct {
+//		ID semanticid.SemanticID `sid:"users"`
+//	}
+//
+// it emits a UserIDKind, a UserID type and the NewUserID, ParseUserID and
+// IsUserID functions into a file named sid_gen.go. Directories to scan
+// can be passed as arguments and default to the current directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	output := flag.String("output", "sid_gen.go", "name of the generated file in each directory")
+	flag.Parse()
+
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+
+	for _, dir := range dirs {
+		src, err := generate(dir, *output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sidgen: %v\n", err)
+			os.Exit(1)
+		}
+
+		if src == nil {
+			continue
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "sidgen: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
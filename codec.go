@@ -0,0 +1,491 @@
+package semanticid
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// A Codec creates, parses and encodes SemanticIDs using its own set of
+// settings. This allows multiple libraries within the same binary to use
+// different separators, defaults and ID providers without touching the
+// package-level variables. A Codec's settings can't be changed after it
+// has been created, and it is safe for concurrent use. The provider
+// registry is the exception: Bind and BindNamed add bindings to it, which
+// affects every Codec sharing that registry. For the default Codec, that
+// is the DefaultProviderRegistry.
+//
+// The package-level functions use a default Codec that is built from
+// the package-level variables, and rebuilt whenever one of them changes.
+// Assigning those variables isn't synchronized, so they should only be
+// set during initialization. Use SetDefaultCodec to change the defaults
+// while the package is in use.
+type Codec struct {
+	separator         string
+	defaultNamespace  string
+	defaultCollection string
+	defaultIDProvider IDProvider
+	providers         *ProviderRegistry
+	nilPolicy         NilPolicy
+}
+
+// CodecOption configures a Codec.
+type CodecOption func(*Codec)
+
+// WithSeparator sets the separator between the parts of a SemanticID.
+// Defaults to `.`.
+func WithSeparator(separator string) CodecOption {
+	return func(c *Codec) {
+		c.separator = separator
+	}
+}
+
+// WithDefaultNamespace sets the namespace that is used if no namespace
+// is specified. Defaults to `namespace`.
+func WithDefaultNamespace(namespace string) CodecOption {
+	return func(c *Codec) {
+		c.defaultNamespace = namespace
+	}
+}
+
+// WithDefaultCollection sets the collection that is used if no
+// collection is specified. Defaults to `collection`.
+func WithDefaultCollection(collection string) CodecOption {
+	return func(c *Codec) {
+		c.defaultCollection = collection
+	}
+}
+
+// WithDefaultIDProvider sets the provider that is used if the provider
+// registry has no binding for a SemanticID. Defaults to a ULIDProvider.
+func WithDefaultIDProvider(idp IDProvider) CodecOption {
+	return func(c *Codec) {
+		c.defaultIDProvider = idp
+	}
+}
+
+// WithProviderRegistry sets the registry that is used to select ID
+// providers by namespace and collection. Defaults to an empty registry.
+func WithProviderRegistry(providers *ProviderRegistry) CodecOption {
+	return func(c *Codec) {
+		c.providers = providers
+	}
+}
+
+// WithNilPolicy sets how nil SemanticIDs are encoded. Defaults to
+// NilAsNull.
+func WithNilPolicy(policy NilPolicy) CodecOption {
+	return func(c *Codec) {
+		c.nilPolicy = policy
+	}
+}
+
+// NewCodec creates a Codec. Settings that aren't specified use the
+// library defaults, regardless of the package-level variables.
+func NewCodec(opts ...CodecOption) *Codec {
+	c := &Codec{
+		separator:         ".",
+		defaultNamespace:  "namespace",
+		defaultCollection: "collection",
+		defaultIDProvider: NewULIDProvider(),
+		providers:         NewProviderRegistry(),
+		nilPolicy:         NilAsNull,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// packageSettings holds the values of the package-level variables that
+// the default Codec was built from.
+type packageSettings struct {
+	separator         string
+	defaultNamespace  string
+	defaultCollection string
+	defaultIDProvider IDProvider
+	providers         *ProviderRegistry
+	nilPolicy         NilPolicy
+}
+
+func currentPackageSettings() packageSettings {
+	return packageSettings{
+		separator:         Separator,
+		defaultNamespace:  DefaultNamespace,
+		defaultCollection: DefaultCollection,
+		defaultIDProvider: DefaultIDProvider,
+		providers:         DefaultProviderRegistry,
+		nilPolicy:         DefaultNilPolicy,
+	}
+}
+
+func (s packageSettings) codec() *Codec {
+	return &Codec{
+		separator:         s.separator,
+		defaultNamespace:  s.defaultNamespace,
+		defaultCollection: s.defaultCollection,
+		defaultIDProvider: s.defaultIDProvider,
+		providers:         s.providers,
+		nilPolicy:         s.nilPolicy,
+	}
+}
+
+type defaultCodecState struct {
+	settings packageSettings
+	codec    *Codec
+	// NOTE: Comparing interfaces panics if their dynamic type isn't
+	// comparable, so a cached Codec with such a provider is rebuilt on
+	// every use. This is checked once when caching, to keep reflection
+	// off the hot path.
+	comparable bool
+}
+
+func newDefaultCodecState(settings packageSettings, c *Codec) *defaultCodecState {
+	t := reflect.TypeOf(settings.defaultIDProvider)
+	return &defaultCodecState{
+		settings:   settings,
+		codec:      c,
+		comparable: t == nil || t.Comparable(),
+	}
+}
+
+var (
+	defaultCodecMu    sync.Mutex
+	defaultCodecValue atomic.Value
+)
+
+// DefaultCodec returns the Codec that is used by the package-level
+// functions. Unless it was replaced using SetDefaultCodec, it is built
+// from the package-level variables.
+func DefaultCodec() *Codec {
+	return defaultCodec()
+}
+
+// SetDefaultCodec replaces the Codec that is used by the package-level
+// functions. Unlike assigning the package-level variables, this is safe
+// while other goroutines are using the package. The Codec stays in use
+// until one of the package-level variables is changed.
+func SetDefaultCodec(c *Codec) {
+	defaultCodecMu.Lock()
+	defer defaultCodecMu.Unlock()
+
+	defaultCodecValue.Store(newDefaultCodecState(currentPackageSettings(), c))
+}
+
+// defaultCodec returns the Codec used by the package-level functions.
+// It is only rebuilt when the package-level variables have changed.
+func defaultCodec() *Codec {
+	settings := currentPackageSettings()
+	if c, ok := cachedDefaultCodec(settings); ok {
+		return c
+	}
+
+	defaultCodecMu.Lock()
+	defer defaultCodecMu.Unlock()
+
+	if c, ok := cachedDefaultCodec(settings); ok {
+		return c
+	}
+
+	c := settings.codec()
+	defaultCodecValue.Store(newDefaultCodecState(settings, c))
+	return c
+}
+
+func cachedDefaultCodec(settings packageSettings) (*Codec, bool) {
+	state, ok := defaultCodecValue.Load().(*defaultCodecState)
+	if !ok || !state.comparable || state.settings != settings {
+		return nil, false
+	}
+
+	return state.codec, true
+}
+
+// Separator returns the separator used by the Codec.
+func (c *Codec) Separator() string {
+	return c.separator
+}
+
+// Bind binds the provider to the given pattern in the Codec's provider
+// registry, splitting the pattern using the Codec's separator. This
+// affects all Codecs sharing the registry.
+func (c *Codec) Bind(pattern string, idp IDProvider) error {
+	if c.providers == nil {
+		return fmt.Errorf("Codec has no provider registry")
+	}
+
+	return c.providers.bind(pattern, c.separator, idp)
+}
+
+// BindNamed works like Bind, but selects a provider that has previously
+// been registered under the given name.
+func (c *Codec) BindNamed(pattern, name string) error {
+	if c.providers == nil {
+		return fmt.Errorf("Codec has no provider registry")
+	}
+
+	return c.providers.bindNamed(pattern, c.separator, name)
+}
+
+// New creates a unique SemanticID with the given namespace and collection.
+func (c *Codec) New(namespace, collection string) (SemanticID, error) {
+	return c.newWithParams(namespace, collection, c.providerFor(namespace, collection))
+}
+
+// NewWithCollection creates a unique SemanticID with the given
+// collection and the default namespace.
+func (c *Codec) NewWithCollection(collection string) (SemanticID, error) {
+	return c.New(c.defaultNamespace, collection)
+}
+
+// NewWithNamespace creates a unique SemanticID with the given
+// namespace and the default collection.
+func (c *Codec) NewWithNamespace(namespace string) (SemanticID, error) {
+	return c.New(namespace, c.defaultCollection)
+}
+
+// NewDefault creates a unique SemanticID with the default namespace
+// and collection.
+func (c *Codec) NewDefault() (SemanticID, error) {
+	return c.New(c.defaultNamespace, c.defaultCollection)
+}
+
+// FromString attempts to parse a given string into a SemanticID.
+func (c *Codec) FromString(s string) (SemanticID, error) {
+	return c.fromStringWithParams(s, nil, true)
+}
+
+// FromStrings attempts to parse a given list of strings into a
+// list of SemanticIDs. An error will be returned for the first
+// conversion that errors.
+func (c *Codec) FromStrings(s []string) ([]SemanticID, error) {
+	result := make([]SemanticID, len(s))
+	for i, id := range s {
+		sid, err := c.FromString(id)
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = sid
+	}
+
+	return result, nil
+}
+
+// String outputs a string representation of the given SemanticID.
+func (c *Codec) String(sid SemanticID) string {
+	if sid.IsNil() {
+		return ""
+	}
+
+	return strings.Join([]string{sid.Namespace, sid.Collection, sid.ID}, c.separator)
+}
+
+// ToStrings converts a list of semanticids to their
+// string representation.
+func (c *Codec) ToStrings(s []SemanticID) []string {
+	result := make([]string, len(s))
+	for i, id := range s {
+		result[i] = c.String(id)
+	}
+
+	return result
+}
+
+// Timestamp returns the time at which the given SemanticID was created,
+// if its ID provider implements TimestampProvider.
+func (c *Codec) Timestamp(sid SemanticID) (time.Time, error) {
+	if sid.IsNil() {
+		return time.Time{}, &SemanticIDError{errEmpty, "The given semantic id was empty"}
+	}
+
+	idp := c.providerFor(sid.Namespace, sid.Collection)
+	tp, ok := idp.(TimestampProvider)
+	if !ok {
+		return time.Time{}, &SemanticIDError{
+			errCode: errNoTimestamp,
+			message: fmt.Sprintf("ID provider %T doesn't embed timestamps", idp),
+		}
+	}
+
+	t, err := tp.Timestamp(sid.ID)
+	if err != nil {
+		return time.Time{}, &SemanticIDError{
+			errCode: errInvalidID,
+			message: fmt.Sprintf("Can't read timestamp from %s: %v", c.String(sid), err),
+		}
+	}
+
+	return t, nil
+}
+
+// Builder creates a SemanticIDBuilder that uses the Codec's settings.
+func (c *Codec) Builder() *SemanticIDBuilder {
+	return &SemanticIDBuilder{
+		codec:      c,
+		namespace:  c.defaultNamespace,
+		collection: c.defaultCollection,
+		idProvider: nil,
+		from:       "",
+		validate:   true,
+	}
+}
+
+// providerFor returns the provider that should be used for SemanticIDs
+// with the given namespace and collection.
+func (c *Codec) providerFor(namespace, collection string) IDProvider {
+	if c.providers != nil {
+		if idp, ok := c.providers.Lookup(namespace, collection); ok {
+			return idp
+		}
+	}
+
+	return c.defaultIDProvider
+}
+
+func (c *Codec) newWithParams(namespace, collection string, idp IDProvider) (SemanticID, error) {
+	id, err := idp.Generate()
+	if err != nil {
+		return empty, &SemanticIDError{
+			errCode: errIDProviderError,
+			message: err.Error(),
+		}
+	}
+
+	return c.newFromParts(namespace, collection, id)
+}
+
+func (c *Codec) newDerivedWithParams(
+	namespace, collection string,
+	idp IDProvider,
+	keyNamespace uuid.UUID,
+	key string,
+) (SemanticID, error) {
+	deriver, ok := idp.(IDDeriver)
+	if !ok {
+		return empty, &SemanticIDError{
+			errCode: errIDProviderError,
+			message: fmt.Sprintf("ID provider %T can't derive IDs from keys", idp),
+		}
+	}
+
+	id, err := deriver.Derive(keyNamespace, key)
+	if err != nil {
+		return empty, &SemanticIDError{
+			errCode: errIDProviderError,
+			message: err.Error(),
+		}
+	}
+
+	if err := idp.Validate(id); err != nil {
+		return empty, &SemanticIDError{
+			errCode: errIDProviderError,
+			message: fmt.Sprintf("Derived ID %s is invalid: %v", id, err),
+		}
+	}
+
+	return c.newFromParts(namespace, collection, id)
+}
+
+func (c *Codec) newFromParts(namespace, collection, id string) (SemanticID, error) {
+	if strings.Contains(namespace, c.separator) {
+		return empty, &SemanticIDError{
+			errCode: errPartContainsSeparator,
+			message: fmt.Sprintf(
+				"Namespace `%s` can't contain the separator (%s)",
+				namespace,
+				c.separator,
+			),
+		}
+	}
+
+	if strings.Contains(collection, c.separator) {
+		return empty, &SemanticIDError{
+			errCode: errPartContainsSeparator,
+			message: fmt.Sprintf(
+				"Collection `%s` can't contain the separator (%s)",
+				collection,
+				c.separator,
+			),
+		}
+	}
+
+	return SemanticID{
+		Namespace:  namespace,
+		Collection: collection,
+		ID:         id,
+	}, nil
+}
+
+// fromStringWithParams parses the given string, validating the ID part
+// with the given provider. If no provider is given, it is selected
+// based on the namespace and collection.
+func (c *Codec) fromStringWithParams(s string, idp IDProvider, validate bool) (SemanticID, error) {
+	if s == "" {
+		return SemanticID{}, &SemanticIDError{errEmpty, "The given string was empty"}
+	}
+
+	parts := strings.SplitN(s, c.separator, 3)
+
+	// SplitN(_, 3) guarantees at most len 3 for the
+	// result, so we only need to check if there aren't enough
+	if len(parts) < 3 {
+		return empty, &SemanticIDError{
+			errCode: errInvalidSID,
+			message: fmt.Sprintf("%s is not a valid semantic id", s),
+		}
+	}
+
+	namespace := parts[0]
+	collection := parts[1]
+	id := parts[2]
+
+	if idp == nil {
+		idp = c.providerFor(namespace, collection)
+	}
+
+	if validate {
+		var err error
+		id, err = checkID(idp, id, s)
+		if err != nil {
+			return empty, err
+		}
+	}
+
+	return SemanticID{
+		Namespace:  namespace,
+		Collection: collection,
+		ID:         id,
+	}, nil
+}
+
+// checkID validates the ID part of the SemanticID described by s with
+// the given provider, and normalizes it if the provider supports it.
+func checkID(idp IDProvider, id, s string) (string, error) {
+	err := idp.Validate(id)
+	if err != nil {
+		return "", &SemanticIDError{
+			errCode: errInvalidID,
+			message: fmt.Sprintf("The UUID section for %s is invalid", s),
+		}
+	}
+
+	if n, ok := idp.(IDNormalizer); ok {
+		id, err = n.Normalize(id)
+		if err != nil {
+			return "", &SemanticIDError{
+				errCode: errInvalidID,
+				message: fmt.Sprintf("The UUID section for %s is invalid", s),
+			}
+		}
+	}
+
+	return id, nil
+}
@@ -0,0 +1,295 @@
+package semanticid_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+
+	"github.com/happenslol/semanticid"
+)
+
+type uncomparableProvider struct {
+	ids []string
+}
+
+func (p uncomparableProvider) Generate() (string, error) { return p.ids[0], nil }
+func (p uncomparableProvider) Validate(id string) error  { return nil }
+
+var _ = Describe("codec", func() {
+	var codec *semanticid.Codec
+
+	BeforeEach(func() {
+		codec = semanticid.NewCodec(
+			semanticid.WithSeparator(":"),
+			semanticid.WithDefaultNamespace("svc"),
+			semanticid.WithDefaultCollection("things"),
+		)
+	})
+
+	Describe("Creating a codec", func() {
+		It("should use the library defaults", func() {
+			defaults := semanticid.NewCodec()
+			Expect(defaults.Separator()).To(Equal("."))
+
+			sid, err := defaults.NewDefault()
+			Expect(err).To(BeNil())
+			Expect(sid.Namespace).To(Equal("namespace"))
+			Expect(sid.Collection).To(Equal("collection"))
+			Expect(semanticid.NewULIDProvider().Validate(sid.ID)).To(BeNil())
+		})
+	})
+
+	Describe("Creating semanticids with a codec", func() {
+		It("should use the codec's defaults", func() {
+			sid, err := codec.NewDefault()
+			Expect(err).To(BeNil())
+			Expect(sid.Namespace).To(Equal("svc"))
+			Expect(sid.Collection).To(Equal("things"))
+
+			sid, err = codec.NewWithCollection("others")
+			Expect(err).To(BeNil())
+			Expect(sid.Namespace).To(Equal("svc"))
+			Expect(sid.Collection).To(Equal("others"))
+
+			sid, err = codec.NewWithNamespace("other")
+			Expect(err).To(BeNil())
+			Expect(sid.Namespace).To(Equal("other"))
+			Expect(sid.Collection).To(Equal("things"))
+		})
+
+		It("should reject the codec's separator in namespace and collection", func() {
+			_, err := codec.New("a:b", "c")
+			Expect(errors.Is(err, semanticid.ErrPartContainsSeparator)).To(BeTrue())
+
+			_, err = codec.New("a.b", "c")
+			Expect(err).To(BeNil())
+		})
+
+		It("should use the codec's default provider", func() {
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(&TestProvider{}))
+			sid, err := codec.NewDefault()
+			Expect(err).To(BeNil())
+			Expect(sid.ID).To(Equal("1234"))
+		})
+
+		It("should use the codec's provider registry", func() {
+			registry := semanticid.NewProviderRegistry()
+			Expect(registry.Bind("test.*", &TestProvider{})).To(BeNil())
+
+			codec := semanticid.NewCodec(semanticid.WithProviderRegistry(registry))
+			sid, err := codec.New("test", "things")
+			Expect(err).To(BeNil())
+			Expect(sid.ID).To(Equal("1234"))
+		})
+
+		It("should bind patterns using the codec's separator", func() {
+			registry := semanticid.NewProviderRegistry()
+			codec := semanticid.NewCodec(
+				semanticid.WithSeparator(":"),
+				semanticid.WithProviderRegistry(registry),
+			)
+
+			Expect(codec.Bind("test:*", &TestProvider{})).To(BeNil())
+			Expect(codec.BindNamed("legacy:users", "uuid")).To(BeNil())
+			Expect(codec.BindNamed("a:b:c", "uuid")).NotTo(BeNil())
+
+			sid, err := codec.New("test", "things")
+			Expect(err).To(BeNil())
+			Expect(sid.ID).To(Equal("1234"))
+
+			sid, err = codec.New("legacy", "users")
+			Expect(err).To(BeNil())
+			Expect(semanticid.NewUUIDProvider().Validate(sid.ID)).To(BeNil())
+		})
+
+		It("should create builders using the codec's settings", func() {
+			sid, err := codec.Builder().WithIDProvider(&TestProvider{}).Build()
+			Expect(err).To(BeNil())
+			Expect(codec.String(sid)).To(Equal("svc:things:1234"))
+		})
+	})
+
+	Describe("Converting semanticids with a codec", func() {
+		It("should use the codec's separator", func() {
+			sid, err := codec.NewDefault()
+			Expect(err).To(BeNil())
+
+			str := codec.String(sid)
+			Expect(strings.Count(str, ":")).To(Equal(2))
+
+			parsed, err := codec.FromString(str)
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(sid))
+
+			_, err = codec.FromString(sid.String())
+			Expect(errors.Is(err, semanticid.ErrInvalid)).To(BeTrue())
+		})
+
+		It("should convert lists", func() {
+			ids := []semanticid.SemanticID{
+				semanticid.Must(codec.NewDefault()),
+				semanticid.Must(codec.NewDefault()),
+			}
+
+			strs := codec.ToStrings(ids)
+			parsed, err := codec.FromStrings(strs)
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(ids))
+		})
+
+		It("should encode and decode json", func() {
+			sid := semanticid.Must(codec.NewDefault())
+
+			m, err := codec.EncodeJSON(sid)
+			Expect(err).To(BeNil())
+			Expect(string(m)).To(Equal("\"" + codec.String(sid) + "\""))
+
+			var result semanticid.SemanticID
+			Expect(codec.DecodeJSON(m, &result)).To(BeNil())
+			Expect(result).To(Equal(sid))
+
+			m, err = codec.EncodeJSON(semanticid.SemanticID{})
+			Expect(err).To(BeNil())
+			Expect(string(m)).To(Equal("null"))
+		})
+
+		It("should encode and decode bson", func() {
+			rb := bsoncodec.NewRegistryBuilder()
+			bsoncodec.DefaultValueDecoders{}.RegisterDefaultDecoders(rb)
+			bsoncodec.DefaultValueEncoders{}.RegisterDefaultEncoders(rb)
+			rb.RegisterCodec(reflect.TypeOf(semanticid.SemanticID{}), codec.BSONCodec())
+			rb.RegisterCodec(reflect.TypeOf(&semanticid.SemanticID{}), codec.BSONPointerCodec())
+			reg := rb.Build()
+
+			sid := semanticid.Must(codec.NewDefault())
+			m, err := bson.MarshalWithRegistry(reg, bson.M{"id": sid, "ptr": &sid})
+			Expect(err).To(BeNil())
+
+			var raw map[string]string
+			Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+			Expect(raw["id"]).To(Equal(codec.String(sid)))
+			Expect(raw["ptr"]).To(Equal(codec.String(sid)))
+
+			var result struct {
+				ID  semanticid.SemanticID  `bson:"id"`
+				Ptr *semanticid.SemanticID `bson:"ptr"`
+			}
+			Expect(bson.UnmarshalWithRegistry(reg, m, &result)).To(BeNil())
+			Expect(result.ID).To(Equal(sid))
+			Expect(*result.Ptr).To(Equal(sid))
+		})
+
+		It("should validate using the codec's settings", func() {
+			type model struct {
+				ID semanticid.SemanticID `validate:"sid=svc:things"`
+			}
+
+			validate := validator.New()
+			Expect(codec.RegisterValidation(validate)).To(BeNil())
+
+			Expect(validate.Struct(&model{ID: semanticid.Must(codec.NewDefault())})).To(BeNil())
+			Expect(validate.Struct(&model{ID: semanticid.Must(codec.NewWithNamespace("other"))})).NotTo(BeNil())
+		})
+	})
+
+	Describe("Using the default codec", func() {
+		AfterEach(func() {
+			semanticid.DefaultNamespace = "namespace"
+		})
+
+		It("should support providers that aren't comparable", func() {
+			previous := semanticid.DefaultIDProvider
+			defer func() { semanticid.DefaultIDProvider = previous }()
+
+			semanticid.DefaultIDProvider = uncomparableProvider{ids: []string{"1234"}}
+			sid := semanticid.Must(semanticid.NewDefault())
+			Expect(sid.ID).To(Equal("1234"))
+			Expect(semanticid.DefaultCodec()).NotTo(BeNil())
+		})
+
+		It("should only be rebuilt when the package-level variables change", func() {
+			defaults := semanticid.DefaultCodec()
+			Expect(semanticid.DefaultCodec()).To(BeIdenticalTo(defaults))
+
+			semanticid.DefaultNamespace = "changed"
+			changed := semanticid.DefaultCodec()
+			Expect(changed).NotTo(BeIdenticalTo(defaults))
+			Expect(semanticid.Must(semanticid.NewDefault()).Namespace).To(Equal("changed"))
+		})
+
+		It("should be replaceable", func() {
+			previous := semanticid.DefaultCodec()
+			defer semanticid.SetDefaultCodec(previous)
+
+			semanticid.SetDefaultCodec(codec)
+			Expect(semanticid.DefaultCodec()).To(BeIdenticalTo(codec))
+
+			sid := semanticid.Must(semanticid.NewDefault())
+			Expect(sid.String()).To(HavePrefix("svc:things:"))
+
+			parsed, err := semanticid.FromString(sid.String())
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(sid))
+		})
+
+		It("should be safe to replace while in use", func() {
+			previous := semanticid.DefaultCodec()
+			defer semanticid.SetDefaultCodec(previous)
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				for i := 0; i < 100; i++ {
+					sid, err := semanticid.NewDefault()
+					Expect(err).To(BeNil())
+					Expect(sid.String()).NotTo(BeEmpty())
+				}
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				for i := 0; i < 100; i++ {
+					semanticid.SetDefaultCodec(codec)
+					semanticid.SetDefaultCodec(previous)
+				}
+			}()
+			wg.Wait()
+		})
+	})
+
+	Describe("Using multiple codecs concurrently", func() {
+		It("should keep their settings separate", func() {
+			other := semanticid.NewCodec(semanticid.WithSeparator("/"))
+
+			var wg sync.WaitGroup
+			for _, c := range []*semanticid.Codec{codec, other} {
+				wg.Add(1)
+				go func(c *semanticid.Codec) {
+					defer GinkgoRecover()
+					defer wg.Done()
+
+					for i := 0; i < 100; i++ {
+						sid, err := c.NewDefault()
+						Expect(err).To(BeNil())
+
+						parsed, err := c.FromString(c.String(sid))
+						Expect(err).To(BeNil())
+						Expect(parsed).To(Equal(sid))
+					}
+				}(c)
+			}
+			wg.Wait()
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package semanticid
+
+import (
+	"sort"
+	"strings"
+)
+
+// IDComparator can optionally be implemented by an IDProvider to order
+// IDs by their meaning instead of their string representation, e.g. by
+// the time they were created at. Compare returns -1, 0 or 1, like
+// strings.Compare.
+type IDComparator interface {
+	Compare(a, b string) int
+}
+
+// Compare orders two SemanticIDs by namespace, then collection, then ID.
+// IDs are compared using the ID provider's IDComparator if it has one,
+// and by their string representation otherwise. Its signature matches
+// what slices.SortFunc expects.
+func Compare(a, b SemanticID) int {
+	return defaultCodec().Compare(a, b)
+}
+
+// Sort sorts the given SemanticIDs in the order defined by Compare.
+func Sort(ids []SemanticID) {
+	defaultCodec().Sort(ids)
+}
+
+// Compare orders the SemanticID relative to the given one, as defined
+// by the package-level Compare function.
+func (sID SemanticID) Compare(other SemanticID) int {
+	return defaultCodec().Compare(sID, other)
+}
+
+// Equal checks whether two SemanticIDs identify the same entity, which
+// is the case if Compare considers them equal.
+func (sID SemanticID) Equal(other SemanticID) bool {
+	return defaultCodec().Compare(sID, other) == 0
+}
+
+// Compare orders two SemanticIDs by namespace, then collection, then ID,
+// using the ID providers selected by the Codec.
+func (c *Codec) Compare(a, b SemanticID) int {
+	if result := strings.Compare(a.Namespace, b.Namespace); result != 0 {
+		return result
+	}
+
+	if result := strings.Compare(a.Collection, b.Collection); result != 0 {
+		return result
+	}
+
+	if cmp, ok := c.providerFor(a.Namespace, a.Collection).(IDComparator); ok {
+		return cmp.Compare(a.ID, b.ID)
+	}
+
+	return strings.Compare(a.ID, b.ID)
+}
+
+// compareInvalid orders two IDs if at least one of them failed to
+// parse. Invalid IDs sort after valid ones and are compared as strings
+// among each other, which keeps the order consistent when sorting a mix
+// of both.
+func compareInvalid(a, b string, errA, errB error) (int, bool) {
+	switch {
+	case errA == nil && errB == nil:
+		return 0, false
+	case errA == nil:
+		return -1, true
+	case errB == nil:
+		return 1, true
+	}
+
+	return strings.Compare(a, b), true
+}
+
+// Equal checks whether two SemanticIDs are considered equal by Compare.
+func (c *Codec) Equal(a, b SemanticID) bool {
+	return c.Compare(a, b) == 0
+}
+
+// Sort sorts the given SemanticIDs in the order defined by Compare.
+func (c *Codec) Sort(ids []SemanticID) {
+	sort.SliceStable(ids, func(i, j int) bool {
+		return c.Compare(ids[i], ids[j]) < 0
+	})
+}
@@ -0,0 +1,137 @@
+package semanticid_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("compare", func() {
+	var (
+		now   time.Time
+		clock func() time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Unix(1600000000, 0)
+		clock = func() time.Time {
+			now = now.Add(time.Millisecond)
+			return now
+		}
+	})
+
+	Describe("Comparing semanticids", func() {
+		It("should order by namespace, then collection, then ID", func() {
+			a := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "3"}
+			b := semanticid.SemanticID{Namespace: "a", Collection: "c", ID: "1"}
+			c := semanticid.SemanticID{Namespace: "b", Collection: "a", ID: "2"}
+
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(&TestProvider{}))
+			Expect(codec.Compare(a, b)).To(Equal(-1))
+			Expect(codec.Compare(b, c)).To(Equal(-1))
+			Expect(codec.Compare(c, a)).To(Equal(1))
+			Expect(codec.Compare(a, a)).To(Equal(0))
+		})
+
+		It("should order ULIDs by time", func() {
+			provider := semanticid.NewULIDProvider(semanticid.WithULIDClock(clock))
+			first := semanticid.Must(semanticid.Builder().WithIDProvider(provider).Build())
+			second := semanticid.Must(semanticid.Builder().WithIDProvider(provider).Build())
+
+			Expect(first.Compare(second)).To(Equal(-1))
+			Expect(second.Compare(first)).To(Equal(1))
+			Expect(semanticid.Compare(first, first)).To(Equal(0))
+		})
+
+		It("should compare ULIDs regardless of case", func() {
+			sid := semanticid.Must(semanticid.NewDefault())
+			lower := sid
+			lower.ID = strings.ToLower(sid.ID)
+
+			Expect(sid.Equal(lower)).To(BeTrue())
+		})
+
+		It("should order snowflake IDs numerically", func() {
+			provider, err := semanticid.NewSnowflakeProvider(1)
+			Expect(err).To(BeNil())
+
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(provider))
+			small := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "999"}
+			large := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "1000"}
+
+			Expect(codec.Compare(small, large)).To(Equal(-1))
+			Expect(codec.Compare(large, small)).To(Equal(1))
+		})
+
+		It("should treat different spellings of a UUID as equal", func() {
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(semanticid.NewUUIDProvider()))
+			a := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}
+			b := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "6BA7B8109DAD11D180B400C04FD430C8"}
+
+			Expect(codec.Equal(a, b)).To(BeTrue())
+		})
+
+		It("should fall back to string comparison for invalid IDs", func() {
+			a := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "x"}
+			b := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "y"}
+
+			Expect(semanticid.Compare(a, b)).To(Equal(-1))
+		})
+
+		It("should sort invalid IDs after valid ones", func() {
+			provider := semanticid.NewULIDProvider(semanticid.WithULIDClock(clock))
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(provider))
+
+			// "0" sorts before any ULID as a string, "z" after
+			valid := semanticid.Must(codec.New("a", "b"))
+			low := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "0"}
+			high := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "z"}
+
+			Expect(codec.Compare(valid, low)).To(Equal(-1))
+			Expect(codec.Compare(low, valid)).To(Equal(1))
+			Expect(codec.Compare(valid, high)).To(Equal(-1))
+			Expect(codec.Compare(low, high)).To(Equal(-1))
+
+			ids := []semanticid.SemanticID{high, valid, low}
+			codec.Sort(ids)
+			Expect(ids).To(Equal([]semanticid.SemanticID{valid, low, high}))
+		})
+
+		It("should be usable as a comparison function", func() {
+			var cmp func(a, b semanticid.SemanticID) int = semanticid.Compare
+			Expect(cmp).NotTo(BeNil())
+		})
+	})
+
+	Describe("Sorting semanticids", func() {
+		It("should sort by namespace, collection and creation time", func() {
+			provider := semanticid.NewULIDProvider(semanticid.WithULIDClock(clock))
+			build := func(namespace, collection string) semanticid.SemanticID {
+				return semanticid.Must(semanticid.Builder().
+					WithIDProvider(provider).
+					WithNamespace(namespace).
+					WithCollection(collection).
+					Build())
+			}
+
+			expected := []semanticid.SemanticID{
+				build("a", "a"),
+				build("a", "a"),
+				build("a", "b"),
+				build("b", "a"),
+				build("b", "a"),
+			}
+
+			ids := []semanticid.SemanticID{
+				expected[3], expected[1], expected[4], expected[2], expected[0],
+			}
+
+			semanticid.Sort(ids)
+			Expect(ids).To(Equal(expected))
+		})
+	})
+})
@@ -8,7 +8,9 @@ require (
 	github.com/oklog/ulid v1.3.1
 	github.com/onsi/ginkgo v1.16.5
 	github.com/onsi/gomega v1.19.0
+	github.com/segmentio/ksuid v1.0.4
 	go.mongodb.org/mongo-driver v1.9.1
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -26,5 +28,4 @@ require (
 	golang.org/x/text v0.3.7 // indirect
 	golang.org/x/tools v0.1.0 // indirect
 	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
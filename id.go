@@ -1,11 +1,18 @@
 package semanticid
 
 import (
+	"bytes"
 	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/oklog/ulid"
+	"github.com/segmentio/ksuid"
 )
 
 // IDProvider represents a type that can generate and validate the ID part
@@ -20,17 +27,112 @@ type IDProvider interface {
 	Validate(id string) error
 }
 
-type ULIDProvider struct{}
+// IDDeriver can optionally be implemented by an IDProvider to
+// deterministically derive IDs from a namespace UUID and a key, so
+// that the same key always results in the same ID.
+type IDDeriver interface {
+	Derive(namespace uuid.UUID, key string) (string, error)
+}
+
+// TimestampProvider can optionally be implemented by an IDProvider whose
+// IDs embed the time they were created at.
+type TimestampProvider interface {
+	Timestamp(id string) (time.Time, error)
+}
+
+// TimeRangeProvider can optionally be implemented by an IDProvider whose
+// IDs are ordered by the time they were created at. MinID and MaxID return
+// the smallest and largest possible ID for the given time, at the
+// precision of the provider's timestamps.
+type TimeRangeProvider interface {
+	MinID(t time.Time) (string, error)
+	MaxID(t time.Time) (string, error)
+}
+
+// StringTimeOrderer can optionally be implemented by a TimeRangeProvider
+// to declare whether its IDs also sort by time when compared as strings.
+// Range queries on stored SemanticIDs, like BSONTimeRangeFilter, are only
+// possible if they do.
+type StringTimeOrderer interface {
+	StringsSortByTime() bool
+}
+
+// BinaryIDProvider can optionally be implemented by an IDProvider whose
+// IDs have a compact binary form, which is then used when encoding
+// SemanticIDs as binary.
+type BinaryIDProvider interface {
+	IDToBytes(id string) ([]byte, error)
+	IDFromBytes(b []byte) (string, error)
+}
+
+// IDNormalizer can optionally be implemented by an IDProvider to
+// rewrite IDs into a canonical form. It is called with IDs that have
+// already been validated, whenever a SemanticID is parsed with validation.
+type IDNormalizer interface {
+	Normalize(id string) (string, error)
+}
+
+// ULIDProvider generates and validates ULIDs. By default, IDs are
+// stamped with the current time and use crypto/rand for entropy, so
+// IDs created later will sort after IDs created earlier.
+type ULIDProvider struct {
+	clock     func() time.Time
+	entropy   io.Reader
+	increment uint64
+}
 
 var _ IDProvider = &ULIDProvider{}
+var _ TimestampProvider = &ULIDProvider{}
+var _ IDComparator = &ULIDProvider{}
+var _ TimeRangeProvider = &ULIDProvider{}
+var _ StringTimeOrderer = &ULIDProvider{}
+var _ BinaryIDProvider = &ULIDProvider{}
+
+// ULIDOption configures a ULIDProvider.
+type ULIDOption func(*ULIDProvider)
+
+// WithULIDClock sets the function that is used to determine the
+// timestamp of generated ULIDs.
+func WithULIDClock(clock func() time.Time) ULIDOption {
+	return func(up *ULIDProvider) {
+		up.clock = clock
+	}
+}
 
-func NewULIDProvider() *ULIDProvider {
-	return &ULIDProvider{}
+// WithULIDEntropy sets the source of the random part of generated ULIDs.
+// The reader has to be safe for concurrent use if the provider is used
+// from multiple goroutines.
+func WithULIDEntropy(entropy io.Reader) ULIDOption {
+	return func(up *ULIDProvider) {
+		up.entropy = entropy
+	}
+}
+
+// WithULIDIncrement sets the upper bound of the random increment that is
+// used between ULIDs generated within the same millisecond. This only
+// affects monotonic providers, and defaults to math.MaxUint32.
+func WithULIDIncrement(increment uint64) ULIDOption {
+	return func(up *ULIDProvider) {
+		up.increment = increment
+	}
+}
+
+func NewULIDProvider(opts ...ULIDOption) *ULIDProvider {
+	up := &ULIDProvider{
+		clock:     time.Now,
+		entropy:   rand.Reader,
+		increment: math.MaxUint32,
+	}
+
+	for _, opt := range opts {
+		opt(up)
+	}
+
+	return up
 }
 
 func (up *ULIDProvider) Generate() (string, error) {
-	t := time.Unix(1000000, 0)
-	result, err := ulid.New(ulid.Timestamp(t), rand.Reader)
+	result, err := ulid.New(ulid.Timestamp(up.clock()), up.entropy)
 	if err != nil {
 		return "", err
 	}
@@ -43,12 +145,224 @@ func (up *ULIDProvider) Validate(id string) error {
 	return err
 }
 
-type UUIDProvider struct{}
+// Timestamp returns the time that is embedded in the given ULID.
+func (up *ULIDProvider) Timestamp(id string) (time.Time, error) {
+	parsed, err := ulid.Parse(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return ulid.Time(parsed.Time()), nil
+}
+
+// Compare orders ULIDs by their binary value, which sorts them by the
+// time they were created at. Invalid ULIDs sort after valid ones.
+func (up *ULIDProvider) Compare(a, b string) int {
+	parsedA, errA := ulid.Parse(a)
+	parsedB, errB := ulid.Parse(b)
+	if result, ok := compareInvalid(a, b, errA, errB); ok {
+		return result
+	}
+
+	return parsedA.Compare(parsedB)
+}
+
+// MinID returns the smallest ULID for the given millisecond.
+func (up *ULIDProvider) MinID(t time.Time) (string, error) {
+	return ulidForTime(t, 0x00)
+}
+
+// MaxID returns the largest ULID for the given millisecond.
+func (up *ULIDProvider) MaxID(t time.Time) (string, error) {
+	return ulidForTime(t, 0xff)
+}
+
+// StringsSortByTime reports that ULIDs sort by time as strings.
+func (up *ULIDProvider) StringsSortByTime() bool {
+	return true
+}
+
+// IDToBytes returns the 16 byte binary form of the given ULID.
+func (up *ULIDProvider) IDToBytes(id string) ([]byte, error) {
+	parsed, err := ulid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed[:], nil
+}
+
+// IDFromBytes converts the 16 byte binary form of a ULID to a string.
+func (up *ULIDProvider) IDFromBytes(b []byte) (string, error) {
+	var result ulid.ULID
+	if err := result.UnmarshalBinary(b); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+func ulidForTime(t time.Time, fill byte) (string, error) {
+	var result ulid.ULID
+	if err := result.SetTime(ulid.Timestamp(t)); err != nil {
+		return "", err
+	}
+
+	if err := result.SetEntropy(bytes.Repeat([]byte{fill}, 10)); err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+// MonotonicULIDProvider generates ULIDs that are strictly increasing,
+// even if they are generated within the same millisecond. It is safe
+// for concurrent use.
+type MonotonicULIDProvider struct {
+	mu      sync.Mutex
+	base    *ULIDProvider
+	entropy io.Reader
+	lastMS  uint64
+}
+
+var _ IDProvider = &MonotonicULIDProvider{}
+var _ TimestampProvider = &MonotonicULIDProvider{}
+var _ IDComparator = &MonotonicULIDProvider{}
+var _ TimeRangeProvider = &MonotonicULIDProvider{}
+var _ StringTimeOrderer = &MonotonicULIDProvider{}
+var _ BinaryIDProvider = &MonotonicULIDProvider{}
+
+func NewMonotonicULIDProvider(opts ...ULIDOption) *MonotonicULIDProvider {
+	base := NewULIDProvider(opts...)
+	return &MonotonicULIDProvider{
+		base:    base,
+		entropy: ulid.Monotonic(base.entropy, base.increment),
+	}
+}
+
+func (mp *MonotonicULIDProvider) Generate() (string, error) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	// NOTE: If the clock moves backwards, we keep using the last
+	// timestamp so that the generated IDs never decrease.
+	ms := ulid.Timestamp(mp.base.clock())
+	if ms < mp.lastMS {
+		ms = mp.lastMS
+	}
+
+	result, err := ulid.New(ms, mp.entropy)
+	if errors.Is(err, ulid.ErrMonotonicOverflow) {
+		return "", fmt.Errorf(
+			"ran out of monotonic entropy for millisecond %d: %w",
+			ms,
+			err,
+		)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	mp.lastMS = ms
+	return result.String(), nil
+}
+
+func (mp *MonotonicULIDProvider) Validate(id string) error {
+	return mp.base.Validate(id)
+}
+
+// Timestamp returns the time that is embedded in the given ULID.
+func (mp *MonotonicULIDProvider) Timestamp(id string) (time.Time, error) {
+	return mp.base.Timestamp(id)
+}
+
+// Compare orders ULIDs by their binary value.
+func (mp *MonotonicULIDProvider) Compare(a, b string) int {
+	return mp.base.Compare(a, b)
+}
+
+// MinID returns the smallest ULID for the given millisecond.
+func (mp *MonotonicULIDProvider) MinID(t time.Time) (string, error) {
+	return mp.base.MinID(t)
+}
+
+// MaxID returns the largest ULID for the given millisecond.
+func (mp *MonotonicULIDProvider) MaxID(t time.Time) (string, error) {
+	return mp.base.MaxID(t)
+}
+
+// StringsSortByTime reports that ULIDs sort by time as strings.
+func (mp *MonotonicULIDProvider) StringsSortByTime() bool {
+	return true
+}
+
+// IDToBytes returns the 16 byte binary form of the given ULID.
+func (mp *MonotonicULIDProvider) IDToBytes(id string) ([]byte, error) {
+	return mp.base.IDToBytes(id)
+}
+
+// IDFromBytes converts the 16 byte binary form of a ULID to a string.
+func (mp *MonotonicULIDProvider) IDFromBytes(b []byte) (string, error) {
+	return mp.base.IDFromBytes(b)
+}
+
+// UUIDFormat determines which spellings of a UUID are accepted by
+// a UUIDProvider.
+type UUIDFormat int
+
+const (
+	// UUIDFormatAny accepts every spelling that can be parsed, including
+	// braced, URN-prefixed and hyphen-less UUIDs, and leaves them as-is.
+	UUIDFormatAny UUIDFormat = iota
+	// UUIDFormatCanonical only accepts the canonical lowercase,
+	// hyphenated form.
+	UUIDFormatCanonical
+	// UUIDFormatNormalize accepts every spelling that can be parsed, and
+	// converts it to the canonical form when parsing a SemanticID.
+	UUIDFormatNormalize
+)
+
+// UUIDProvider generates random version 4 UUIDs and validates UUIDs.
+type UUIDProvider struct {
+	versions []byte
+	format   UUIDFormat
+}
 
 var _ IDProvider = &UUIDProvider{}
+var _ IDNormalizer = &UUIDProvider{}
+var _ IDDeriver = &UUIDProvider{}
+var _ TimestampProvider = &UUIDProvider{}
+var _ IDComparator = &UUIDProvider{}
+var _ BinaryIDProvider = &UUIDProvider{}
+
+// UUIDOption configures a UUIDProvider.
+type UUIDOption func(*UUIDProvider)
+
+// WithUUIDVersions restricts validation to the given UUID versions.
+// This does not affect generation, which always creates version 4
+// UUIDs, so uuid.V4 should be included if the provider is also used
+// to generate IDs.
+func WithUUIDVersions(versions ...byte) UUIDOption {
+	return func(up *UUIDProvider) {
+		up.versions = versions
+	}
+}
 
-func NewUUIDProvider() *UUIDProvider {
-	return &UUIDProvider{}
+// WithUUIDFormat sets which spellings of a UUID are accepted.
+func WithUUIDFormat(format UUIDFormat) UUIDOption {
+	return func(up *UUIDProvider) {
+		up.format = format
+	}
+}
+
+func NewUUIDProvider(opts ...UUIDOption) *UUIDProvider {
+	up := &UUIDProvider{format: UUIDFormatAny}
+	for _, opt := range opts {
+		opt(up)
+	}
+
+	return up
 }
 
 func (up *UUIDProvider) Generate() (string, error) {
@@ -60,7 +374,477 @@ func (up *UUIDProvider) Generate() (string, error) {
 	return result.String(), nil
 }
 
+// Derive creates a name-based version 5 UUID from the given namespace
+// and key. Make sure to include uuid.V5 if the provider's versions are
+// restricted.
+func (up *UUIDProvider) Derive(namespace uuid.UUID, key string) (string, error) {
+	return uuid.NewV5(namespace, key).String(), nil
+}
+
 func (up *UUIDProvider) Validate(id string) error {
-	_, err := uuid.FromString(id)
+	_, err := up.parse(id)
 	return err
 }
+
+// Timestamp returns the time that is embedded in the given UUID. Only
+// version 1, 6 and 7 UUIDs contain a timestamp.
+func (up *UUIDProvider) Timestamp(id string) (time.Time, error) {
+	parsed, err := up.parse(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	switch parsed.Version() {
+	case uuid.V1:
+		ts, err := uuid.TimestampFromV1(parsed)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return ts.Time()
+	case uuid.V6:
+		ts, err := uuid.TimestampFromV6(parsed)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return ts.Time()
+	case uuid.V7:
+		return uuidV7Time(parsed), nil
+	}
+
+	return time.Time{}, fmt.Errorf(
+		"uuid %s is version %d, which doesn't contain a timestamp",
+		id,
+		parsed.Version(),
+	)
+}
+
+// Compare orders UUIDs by their binary value, so that different
+// spellings of the same UUID are equal and version 7 UUIDs are sorted
+// by time. Invalid UUIDs sort after valid ones.
+func (up *UUIDProvider) Compare(a, b string) int {
+	return compareUUIDs(a, b)
+}
+
+// IDToBytes returns the 16 byte binary form of the given UUID.
+func (up *UUIDProvider) IDToBytes(id string) ([]byte, error) {
+	parsed, err := up.parse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.Bytes(), nil
+}
+
+// IDFromBytes converts the 16 byte binary form of a UUID to its
+// canonical string form.
+func (up *UUIDProvider) IDFromBytes(b []byte) (string, error) {
+	return uuidFromBytes(b)
+}
+
+// Normalize converts the given UUID to its canonical form if the
+// provider uses UUIDFormatNormalize, and returns it unchanged otherwise.
+func (up *UUIDProvider) Normalize(id string) (string, error) {
+	parsed, err := up.parse(id)
+	if err != nil {
+		return "", err
+	}
+
+	if up.format != UUIDFormatNormalize {
+		return id, nil
+	}
+
+	return parsed.String(), nil
+}
+
+func (up *UUIDProvider) parse(id string) (uuid.UUID, error) {
+	parsed, err := uuid.FromString(id)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if up.format == UUIDFormatCanonical && parsed.String() != id {
+		return uuid.Nil, fmt.Errorf("uuid %s is not in canonical form", id)
+	}
+
+	if len(up.versions) == 0 {
+		return parsed, nil
+	}
+
+	for _, v := range up.versions {
+		if parsed.Version() == v {
+			return parsed, nil
+		}
+	}
+
+	return uuid.Nil, fmt.Errorf(
+		"uuid %s has version %d, which is not allowed",
+		id,
+		parsed.Version(),
+	)
+}
+
+// UUIDv7Provider generates and validates time-ordered version 7 UUIDs
+// as specified in RFC 9562. The first 48 bits of each UUID contain the
+// unix timestamp in milliseconds, so IDs created later will sort after
+// IDs created earlier, while still using the canonical UUID format.
+type UUIDv7Provider struct {
+	clock   func() time.Time
+	entropy io.Reader
+}
+
+var _ IDProvider = &UUIDv7Provider{}
+var _ TimestampProvider = &UUIDv7Provider{}
+var _ IDComparator = &UUIDv7Provider{}
+var _ TimeRangeProvider = &UUIDv7Provider{}
+var _ StringTimeOrderer = &UUIDv7Provider{}
+var _ BinaryIDProvider = &UUIDv7Provider{}
+
+// UUIDv7Option configures a UUIDv7Provider.
+type UUIDv7Option func(*UUIDv7Provider)
+
+// WithUUIDv7Clock sets the function that is used to determine the
+// timestamp of generated UUIDs.
+func WithUUIDv7Clock(clock func() time.Time) UUIDv7Option {
+	return func(up *UUIDv7Provider) {
+		up.clock = clock
+	}
+}
+
+// WithUUIDv7Entropy sets the source of the random part of generated UUIDs.
+// The reader has to be safe for concurrent use if the provider is used
+// from multiple goroutines.
+func WithUUIDv7Entropy(entropy io.Reader) UUIDv7Option {
+	return func(up *UUIDv7Provider) {
+		up.entropy = entropy
+	}
+}
+
+func NewUUIDv7Provider(opts ...UUIDv7Option) *UUIDv7Provider {
+	up := &UUIDv7Provider{
+		clock:   time.Now,
+		entropy: rand.Reader,
+	}
+
+	for _, opt := range opts {
+		opt(up)
+	}
+
+	return up
+}
+
+func (up *UUIDv7Provider) Generate() (string, error) {
+	var result uuid.UUID
+	if _, err := io.ReadFull(up.entropy, result[6:]); err != nil {
+		return "", err
+	}
+
+	ms := up.clock().UnixMilli()
+	if ms < 0 || ms > maxUUIDv7Time {
+		return "", fmt.Errorf("timestamp %d can't be encoded in a UUIDv7", ms)
+	}
+
+	putUUIDv7Time(&result, uint64(ms))
+	result.SetVersion(uuid.V7)
+	result.SetVariant(uuid.VariantRFC4122)
+
+	return result.String(), nil
+}
+
+// Validate only accepts version 7 UUIDs with the RFC 9562 variant.
+func (up *UUIDv7Provider) Validate(id string) error {
+	parsed, err := uuid.FromString(id)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Version() != uuid.V7 {
+		return fmt.Errorf("uuid %s is version %d, not version 7", id, parsed.Version())
+	}
+
+	if parsed.Variant() != uuid.VariantRFC4122 {
+		return fmt.Errorf("uuid %s does not use the RFC 9562 variant", id)
+	}
+
+	return nil
+}
+
+// Timestamp returns the time that is embedded in the given UUID.
+func (up *UUIDv7Provider) Timestamp(id string) (time.Time, error) {
+	if err := up.Validate(id); err != nil {
+		return time.Time{}, err
+	}
+
+	parsed, err := uuid.FromString(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return uuidV7Time(parsed), nil
+}
+
+// Compare orders UUIDs by their binary value, which sorts them by the
+// time they were created at. Invalid UUIDs sort after valid ones.
+func (up *UUIDv7Provider) Compare(a, b string) int {
+	return compareUUIDs(a, b)
+}
+
+// MinID returns the smallest version 7 UUID for the given millisecond.
+func (up *UUIDv7Provider) MinID(t time.Time) (string, error) {
+	return uuidV7ForTime(t, 0x00)
+}
+
+// MaxID returns the largest version 7 UUID for the given millisecond.
+func (up *UUIDv7Provider) MaxID(t time.Time) (string, error) {
+	return uuidV7ForTime(t, 0xff)
+}
+
+// StringsSortByTime reports that version 7 UUIDs sort by time as
+// strings, which holds for the lowercase canonical form they are
+// generated in.
+func (up *UUIDv7Provider) StringsSortByTime() bool {
+	return true
+}
+
+// IDToBytes returns the 16 byte binary form of the given UUID.
+func (up *UUIDv7Provider) IDToBytes(id string) ([]byte, error) {
+	if err := up.Validate(id); err != nil {
+		return nil, err
+	}
+
+	parsed, err := uuid.FromString(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.Bytes(), nil
+}
+
+// IDFromBytes converts the 16 byte binary form of a UUID to its
+// canonical string form.
+func (up *UUIDv7Provider) IDFromBytes(b []byte) (string, error) {
+	return uuidFromBytes(b)
+}
+
+func uuidFromBytes(b []byte) (string, error) {
+	result, err := uuid.FromBytes(b)
+	if err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+func uuidV7ForTime(t time.Time, fill byte) (string, error) {
+	ms := t.UnixMilli()
+	if ms < 0 || ms > maxUUIDv7Time {
+		return "", fmt.Errorf("timestamp %d can't be encoded in a UUIDv7", ms)
+	}
+
+	var result uuid.UUID
+	for i := 6; i < len(result); i++ {
+		result[i] = fill
+	}
+
+	putUUIDv7Time(&result, uint64(ms))
+	result.SetVersion(uuid.V7)
+	result.SetVariant(uuid.VariantRFC4122)
+
+	return result.String(), nil
+}
+
+func compareUUIDs(a, b string) int {
+	parsedA, errA := uuid.FromString(a)
+	parsedB, errB := uuid.FromString(b)
+	if result, ok := compareInvalid(a, b, errA, errB); ok {
+		return result
+	}
+
+	return bytes.Compare(parsedA.Bytes(), parsedB.Bytes())
+}
+
+const maxUUIDv7Time = 1<<48 - 1
+
+func uuidV7Time(u uuid.UUID) time.Time {
+	ms := uint64(u[0])<<40 |
+		uint64(u[1])<<32 |
+		uint64(u[2])<<24 |
+		uint64(u[3])<<16 |
+		uint64(u[4])<<8 |
+		uint64(u[5])
+
+	return time.UnixMilli(int64(ms))
+}
+
+func putUUIDv7Time(u *uuid.UUID, ms uint64) {
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+}
+
+// KSUIDProvider generates and validates KSUIDs, which are 27 character
+// base62 strings that contain a timestamp with second precision,
+// followed by a random payload.
+type KSUIDProvider struct {
+	clock   func() time.Time
+	entropy io.Reader
+}
+
+var _ IDProvider = &KSUIDProvider{}
+var _ TimestampProvider = &KSUIDProvider{}
+var _ IDComparator = &KSUIDProvider{}
+var _ TimeRangeProvider = &KSUIDProvider{}
+var _ StringTimeOrderer = &KSUIDProvider{}
+var _ BinaryIDProvider = &KSUIDProvider{}
+
+// KSUIDOption configures a KSUIDProvider.
+type KSUIDOption func(*KSUIDProvider)
+
+// WithKSUIDClock sets the function that is used to determine the
+// timestamp of generated KSUIDs.
+func WithKSUIDClock(clock func() time.Time) KSUIDOption {
+	return func(kp *KSUIDProvider) {
+		kp.clock = clock
+	}
+}
+
+// WithKSUIDEntropy sets the source of the random payload of generated
+// KSUIDs. The reader has to be safe for concurrent use if the provider
+// is used from multiple goroutines.
+func WithKSUIDEntropy(entropy io.Reader) KSUIDOption {
+	return func(kp *KSUIDProvider) {
+		kp.entropy = entropy
+	}
+}
+
+func NewKSUIDProvider(opts ...KSUIDOption) *KSUIDProvider {
+	kp := &KSUIDProvider{
+		clock:   time.Now,
+		entropy: rand.Reader,
+	}
+
+	for _, opt := range opts {
+		opt(kp)
+	}
+
+	return kp
+}
+
+func (kp *KSUIDProvider) Generate() (string, error) {
+	t := kp.clock()
+	if t.Before(minKSUIDTime) || t.After(maxKSUIDTime) {
+		return "", fmt.Errorf("time %s can't be encoded in a KSUID", t)
+	}
+
+	var payload [16]byte
+	if _, err := io.ReadFull(kp.entropy, payload[:]); err != nil {
+		return "", err
+	}
+
+	result, err := ksuid.FromParts(t, payload[:])
+	if err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+func (kp *KSUIDProvider) Validate(id string) error {
+	_, err := parseKSUID(id)
+	return err
+}
+
+// Timestamp returns the time that is embedded in the given KSUID.
+func (kp *KSUIDProvider) Timestamp(id string) (time.Time, error) {
+	parsed, err := parseKSUID(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parsed.Time(), nil
+}
+
+// Compare orders KSUIDs by their binary value, which sorts them by the
+// time they were created at. Invalid KSUIDs sort after valid ones.
+func (kp *KSUIDProvider) Compare(a, b string) int {
+	parsedA, errA := parseKSUID(a)
+	parsedB, errB := parseKSUID(b)
+	if result, ok := compareInvalid(a, b, errA, errB); ok {
+		return result
+	}
+
+	return ksuid.Compare(parsedA, parsedB)
+}
+
+// MinID returns the smallest KSUID for the given second.
+func (kp *KSUIDProvider) MinID(t time.Time) (string, error) {
+	return ksuidForTime(t, 0x00)
+}
+
+// MaxID returns the largest KSUID for the given second.
+func (kp *KSUIDProvider) MaxID(t time.Time) (string, error) {
+	return ksuidForTime(t, 0xff)
+}
+
+// StringsSortByTime reports that KSUIDs sort by time as strings.
+func (kp *KSUIDProvider) StringsSortByTime() bool {
+	return true
+}
+
+// IDToBytes returns the 20 byte binary form of the given KSUID.
+func (kp *KSUIDProvider) IDToBytes(id string) ([]byte, error) {
+	parsed, err := parseKSUID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.Bytes(), nil
+}
+
+// IDFromBytes converts the 20 byte binary form of a KSUID to a string.
+func (kp *KSUIDProvider) IDFromBytes(b []byte) (string, error) {
+	result, err := ksuid.FromBytes(b)
+	if err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+func ksuidForTime(t time.Time, fill byte) (string, error) {
+	if t.Before(minKSUIDTime) || t.After(maxKSUIDTime) {
+		return "", fmt.Errorf("time %s can't be encoded in a KSUID", t)
+	}
+
+	result, err := ksuid.FromParts(t, bytes.Repeat([]byte{fill}, 16))
+	if err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+var (
+	minKSUIDTime = ksuid.Nil.Time()
+	maxKSUIDTime = ksuid.Max.Time()
+)
+
+// parseKSUID parses the given KSUID and makes sure that it is encoded
+// correctly, since ksuid.Parse accepts characters outside of the
+// base62 alphabet and values that overflow 160 bits.
+func parseKSUID(id string) (ksuid.KSUID, error) {
+	parsed, err := ksuid.Parse(id)
+	if err != nil {
+		return ksuid.Nil, err
+	}
+
+	if parsed.String() != id {
+		return ksuid.Nil, fmt.Errorf("%s is not a valid ksuid", id)
+	}
+
+	return parsed, nil
+}
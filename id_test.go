@@ -1,12 +1,20 @@
 package semanticid_test
 
 import (
+	"bytes"
 	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
 
-	"github.com/oklog/ulid"
 	"github.com/gofrs/uuid"
+	"github.com/oklog/ulid"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/segmentio/ksuid"
 
 	"github.com/happenslol/semanticid"
 )
@@ -28,6 +36,54 @@ var _ = Describe("id", func() {
 				_, err = ulid.Parse(id)
 				Expect(err).To(BeNil())
 			})
+
+			It("should stamp IDs with the current time", func() {
+				before := ulid.Timestamp(time.Now())
+				id, err := ulidProvider.Generate()
+				Expect(err).To(BeNil())
+				after := ulid.Timestamp(time.Now())
+
+				parsed, err := ulid.Parse(id)
+				Expect(err).To(BeNil())
+				Expect(parsed.Time()).To(BeNumerically(">=", before))
+				Expect(parsed.Time()).To(BeNumerically("<=", after))
+			})
+
+			It("should sort IDs generated later after earlier ones", func() {
+				now := time.Unix(1600000000, 0)
+				clock := func() time.Time {
+					now = now.Add(time.Millisecond)
+					return now
+				}
+
+				provider := semanticid.NewULIDProvider(semanticid.WithULIDClock(clock))
+
+				ids := make([]string, 100)
+				for i := range ids {
+					id, err := provider.Generate()
+					Expect(err).To(BeNil())
+					ids[i] = id
+				}
+
+				Expect(sort.StringsAreSorted(ids)).To(BeTrue())
+			})
+
+			It("should use the given clock and entropy source", func() {
+				t := time.Unix(1600000000, 0)
+				entropy := bytes.NewReader(make([]byte, 10))
+				provider := semanticid.NewULIDProvider(
+					semanticid.WithULIDClock(func() time.Time { return t }),
+					semanticid.WithULIDEntropy(entropy),
+				)
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				parsed, err := ulid.Parse(id)
+				Expect(err).To(BeNil())
+				Expect(parsed.Time()).To(Equal(ulid.Timestamp(t)))
+				Expect(parsed.Entropy()).To(Equal(make([]byte, 10)))
+			})
 		})
 
 		Context("to validate IDs", func() {
@@ -44,6 +100,133 @@ var _ = Describe("id", func() {
 				Expect(err).NotTo(BeNil())
 			})
 		})
+
+		Context("to extract timestamps", func() {
+			It("should return the embedded time", func() {
+				t := time.UnixMilli(1600000000123)
+				provider := semanticid.NewULIDProvider(
+					semanticid.WithULIDClock(func() time.Time { return t }),
+				)
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				result, err := provider.Timestamp(id)
+				Expect(err).To(BeNil())
+				Expect(result.Equal(t)).To(BeTrue())
+			})
+
+			It("should reject invalid IDs", func() {
+				_, err := semanticid.NewULIDProvider().Timestamp("1234")
+				Expect(err).NotTo(BeNil())
+			})
+		})
+	})
+
+	Describe("Using the monotonic ULID provider", func() {
+		var (
+			now   time.Time
+			clock func() time.Time
+		)
+
+		BeforeEach(func() {
+			now = time.Unix(1600000000, 0)
+			clock = func() time.Time { return now }
+		})
+
+		Context("to generate IDs", func() {
+			It("should generate strictly increasing IDs within the same millisecond", func() {
+				provider := semanticid.NewMonotonicULIDProvider(semanticid.WithULIDClock(clock))
+
+				prev := ""
+				for i := 0; i < 1000; i++ {
+					id, err := provider.Generate()
+					Expect(err).To(BeNil())
+					Expect(id > prev).To(BeTrue())
+					prev = id
+				}
+			})
+
+			It("should keep increasing when the clock moves backwards", func() {
+				provider := semanticid.NewMonotonicULIDProvider(semanticid.WithULIDClock(clock))
+
+				first, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				now = now.Add(-time.Second)
+				second, err := provider.Generate()
+				Expect(err).To(BeNil())
+				Expect(second > first).To(BeTrue())
+			})
+
+			It("should generate unique increasing IDs from many goroutines", func() {
+				provider := semanticid.NewMonotonicULIDProvider()
+
+				const workers = 16
+				const perWorker = 500
+
+				results := make([][]string, workers)
+				var wg sync.WaitGroup
+				for w := 0; w < workers; w++ {
+					wg.Add(1)
+					go func(w int) {
+						defer GinkgoRecover()
+						defer wg.Done()
+
+						ids := make([]string, perWorker)
+						for i := range ids {
+							id, err := provider.Generate()
+							Expect(err).To(BeNil())
+							ids[i] = id
+						}
+
+						results[w] = ids
+					}(w)
+				}
+				wg.Wait()
+
+				seen := make(map[string]struct{}, workers*perWorker)
+				for _, ids := range results {
+					Expect(sort.StringsAreSorted(ids)).To(BeTrue())
+					for _, id := range ids {
+						seen[id] = struct{}{}
+					}
+				}
+
+				Expect(seen).To(HaveLen(workers * perWorker))
+			})
+
+			It("should return an ID provider error when the entropy overflows", func() {
+				provider := semanticid.NewMonotonicULIDProvider(
+					semanticid.WithULIDClock(clock),
+					semanticid.WithULIDEntropy(bytes.NewReader(bytes.Repeat([]byte{0xff}, 10))),
+					semanticid.WithULIDIncrement(1),
+				)
+
+				_, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				_, err = provider.Generate()
+				Expect(errors.Is(err, ulid.ErrMonotonicOverflow)).To(BeTrue())
+
+				_, err = semanticid.Builder().WithIDProvider(provider).Build()
+				Expect(errors.Is(err, semanticid.ErrIDProvider)).To(BeTrue())
+			})
+		})
+
+		Context("to validate IDs", func() {
+			It("should accept valid IDs", func() {
+				provider := semanticid.NewMonotonicULIDProvider()
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+				Expect(provider.Validate(id)).To(BeNil())
+			})
+
+			It("should reject invalid IDs", func() {
+				provider := semanticid.NewMonotonicULIDProvider()
+				Expect(provider.Validate("1234")).NotTo(BeNil())
+			})
+		})
 	})
 
 	Describe("Using the UUID provider", func() {
@@ -77,6 +260,315 @@ var _ = Describe("id", func() {
 				err := uuidProvider.Validate("1234")
 				Expect(err).NotTo(BeNil())
 			})
+
+			It("should accept non-canonical spellings by default", func() {
+				id := "6BA7B810-9DAD-11D1-80B4-00C04FD430C8"
+				Expect(uuidProvider.Validate(id)).To(BeNil())
+				Expect(uuidProvider.Validate("{" + id + "}")).To(BeNil())
+				Expect(uuidProvider.Validate("urn:uuid:" + id)).To(BeNil())
+				Expect(uuidProvider.Validate("6ba7b8109dad11d180b400c04fd430c8")).To(BeNil())
+			})
+		})
+
+		Context("to extract timestamps", func() {
+			It("should return the time embedded in version 1 UUIDs", func() {
+				before := time.Now().Add(-time.Second)
+				v1, err := uuid.NewV1()
+				Expect(err).To(BeNil())
+
+				result, err := semanticid.NewUUIDProvider().Timestamp(v1.String())
+				Expect(err).To(BeNil())
+				Expect(result).To(BeTemporally("~", before, 2*time.Second))
+			})
+
+			It("should return the time embedded in version 7 UUIDs", func() {
+				t := time.UnixMilli(1600000000123)
+				id, err := semanticid.NewUUIDv7Provider(
+					semanticid.WithUUIDv7Clock(func() time.Time { return t }),
+				).Generate()
+				Expect(err).To(BeNil())
+
+				result, err := semanticid.NewUUIDProvider().Timestamp(id)
+				Expect(err).To(BeNil())
+				Expect(result.Equal(t)).To(BeTrue())
+			})
+
+			It("should reject versions without a timestamp", func() {
+				v4, err := uuid.NewV4()
+				Expect(err).To(BeNil())
+
+				_, err = semanticid.NewUUIDProvider().Timestamp(v4.String())
+				Expect(err).NotTo(BeNil())
+			})
+		})
+
+		Context("with restricted versions", func() {
+			It("should only accept the allowed versions", func() {
+				provider := semanticid.NewUUIDProvider(semanticid.WithUUIDVersions(uuid.V4))
+
+				v4, err := uuid.NewV4()
+				Expect(err).To(BeNil())
+				Expect(provider.Validate(v4.String())).To(BeNil())
+
+				v1, err := uuid.NewV1()
+				Expect(err).To(BeNil())
+				Expect(provider.Validate(v1.String())).NotTo(BeNil())
+			})
+		})
+
+		Context("with the canonical format", func() {
+			It("should reject non-canonical spellings", func() {
+				provider := semanticid.NewUUIDProvider(
+					semanticid.WithUUIDFormat(semanticid.UUIDFormatCanonical),
+				)
+
+				Expect(provider.Validate("6ba7b810-9dad-11d1-80b4-00c04fd430c8")).To(BeNil())
+				Expect(provider.Validate("6BA7B810-9DAD-11D1-80B4-00C04FD430C8")).NotTo(BeNil())
+				Expect(provider.Validate("{6ba7b810-9dad-11d1-80b4-00c04fd430c8}")).NotTo(BeNil())
+				Expect(provider.Validate("6ba7b8109dad11d180b400c04fd430c8")).NotTo(BeNil())
+
+				_, err := semanticid.Builder().
+					WithIDProvider(provider).
+					FromString("a.b.urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8").
+					Build()
+				Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+			})
+		})
+
+		Context("with the normalizing format", func() {
+			It("should normalize non-canonical spellings when parsing", func() {
+				provider := semanticid.NewUUIDProvider(
+					semanticid.WithUUIDFormat(semanticid.UUIDFormatNormalize),
+				)
+
+				canonical := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+				for _, id := range []string{
+					canonical,
+					"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+					"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+					"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+					"6ba7b8109dad11d180b400c04fd430c8",
+				} {
+					sid, err := semanticid.Builder().
+						WithIDProvider(provider).
+						FromString("a.b." + id).
+						Build()
+					Expect(err).To(BeNil())
+					Expect(sid.ID).To(Equal(canonical))
+				}
+			})
+
+			It("should leave IDs untouched when validation is disabled", func() {
+				provider := semanticid.NewUUIDProvider(
+					semanticid.WithUUIDFormat(semanticid.UUIDFormatNormalize),
+				)
+
+				sid, err := semanticid.Builder().
+					WithIDProvider(provider).
+					FromString("a.b.6BA7B810-9DAD-11D1-80B4-00C04FD430C8").
+					NoValidate().
+					Build()
+				Expect(err).To(BeNil())
+				Expect(sid.ID).To(Equal("6BA7B810-9DAD-11D1-80B4-00C04FD430C8"))
+			})
+		})
+	})
+	Describe("Using the UUIDv7 provider", func() {
+		var uuidv7Provider semanticid.IDProvider
+
+		BeforeEach(func() {
+			uuidv7Provider = semanticid.NewUUIDv7Provider()
+		})
+
+		Context("to generate IDs", func() {
+			It("should generate valid version 7 IDs", func() {
+				id, err := uuidv7Provider.Generate()
+				Expect(err).To(BeNil())
+
+				parsed, err := uuid.FromString(id)
+				Expect(err).To(BeNil())
+				Expect(parsed.Version()).To(Equal(uuid.V7))
+				Expect(parsed.Variant()).To(Equal(uuid.VariantRFC4122))
+				Expect(parsed.String()).To(Equal(id))
+			})
+
+			It("should encode the timestamp in the first 48 bits", func() {
+				t := time.UnixMilli(1600000000123)
+				provider := semanticid.NewUUIDv7Provider(
+					semanticid.WithUUIDv7Clock(func() time.Time { return t }),
+				)
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				parsed, err := uuid.FromString(id)
+				Expect(err).To(BeNil())
+
+				ms := binary.BigEndian.Uint64(append([]byte{0, 0}, parsed[:6]...))
+				Expect(ms).To(Equal(uint64(1600000000123)))
+			})
+
+			It("should sort IDs generated later after earlier ones", func() {
+				now := time.Unix(1600000000, 0)
+				clock := func() time.Time {
+					now = now.Add(time.Millisecond)
+					return now
+				}
+
+				provider := semanticid.NewUUIDv7Provider(semanticid.WithUUIDv7Clock(clock))
+
+				ids := make([]string, 100)
+				for i := range ids {
+					id, err := provider.Generate()
+					Expect(err).To(BeNil())
+					ids[i] = id
+				}
+
+				Expect(sort.StringsAreSorted(ids)).To(BeTrue())
+			})
+		})
+
+		Context("to validate IDs", func() {
+			It("should accept valid IDs", func() {
+				id, err := uuidv7Provider.Generate()
+				Expect(err).To(BeNil())
+				Expect(uuidv7Provider.Validate(id)).To(BeNil())
+			})
+
+			It("should return the embedded time", func() {
+				t := time.UnixMilli(1600000000123)
+				provider := semanticid.NewUUIDv7Provider(
+					semanticid.WithUUIDv7Clock(func() time.Time { return t }),
+				)
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				result, err := provider.Timestamp(id)
+				Expect(err).To(BeNil())
+				Expect(result.Equal(t)).To(BeTrue())
+			})
+
+			It("should reject UUIDs of other versions", func() {
+				v4, err := uuid.NewV4()
+				Expect(err).To(BeNil())
+				Expect(uuidv7Provider.Validate(v4.String())).NotTo(BeNil())
+			})
+
+			It("should reject invalid IDs", func() {
+				Expect(uuidv7Provider.Validate("1234")).NotTo(BeNil())
+			})
+		})
+	})
+	Describe("Using the KSUID provider", func() {
+		var ksuidProvider *semanticid.KSUIDProvider
+
+		BeforeEach(func() {
+			ksuidProvider = semanticid.NewKSUIDProvider()
+		})
+
+		Context("to generate IDs", func() {
+			It("should generate valid IDs", func() {
+				id, err := ksuidProvider.Generate()
+				Expect(err).To(BeNil())
+				Expect(id).To(HaveLen(27))
+
+				_, err = ksuid.Parse(id)
+				Expect(err).To(BeNil())
+			})
+
+			It("should sort IDs generated later after earlier ones", func() {
+				now := time.Unix(1600000000, 0)
+				clock := func() time.Time {
+					now = now.Add(time.Second)
+					return now
+				}
+
+				provider := semanticid.NewKSUIDProvider(semanticid.WithKSUIDClock(clock))
+
+				ids := make([]string, 100)
+				for i := range ids {
+					id, err := provider.Generate()
+					Expect(err).To(BeNil())
+					ids[i] = id
+				}
+
+				Expect(sort.StringsAreSorted(ids)).To(BeTrue())
+			})
+
+			It("should be usable for semanticids", func() {
+				sid, err := semanticid.Builder().WithIDProvider(ksuidProvider).Build()
+				Expect(err).To(BeNil())
+
+				parsed, err := semanticid.Builder().
+					WithIDProvider(ksuidProvider).
+					FromString(sid.String()).
+					Build()
+				Expect(err).To(BeNil())
+				Expect(parsed).To(Equal(sid))
+			})
+		})
+
+		Context("to validate IDs", func() {
+			It("should accept valid IDs", func() {
+				Expect(ksuidProvider.Validate(ksuid.New().String())).To(BeNil())
+			})
+
+			It("should reject invalid IDs", func() {
+				Expect(ksuidProvider.Validate("1234")).NotTo(BeNil())
+				Expect(ksuidProvider.Validate("0ujtsYcgvSTl8PAuAdqWYSMnLO-")).NotTo(BeNil())
+				Expect(ksuidProvider.Validate("zzzzzzzzzzzzzzzzzzzzzzzzzzz")).NotTo(BeNil())
+			})
+		})
+
+		Context("to extract timestamps", func() {
+			It("should return the embedded time", func() {
+				t := time.Unix(1600000000, 0)
+				provider := semanticid.NewKSUIDProvider(
+					semanticid.WithKSUIDClock(func() time.Time { return t }),
+				)
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				result, err := provider.Timestamp(id)
+				Expect(err).To(BeNil())
+				Expect(result.Equal(t)).To(BeTrue())
+			})
+
+			It("should reject invalid IDs", func() {
+				_, err := ksuidProvider.Timestamp("1234")
+				Expect(err).NotTo(BeNil())
+			})
 		})
 	})
 })
+
+func BenchmarkULIDProvider(b *testing.B) {
+	provider := semanticid.NewULIDProvider()
+	for i := 0; i < b.N; i++ {
+		if _, err := provider.Generate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMonotonicULIDProvider(b *testing.B) {
+	provider := semanticid.NewMonotonicULIDProvider()
+	for i := 0; i < b.N; i++ {
+		if _, err := provider.Generate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMonotonicULIDProviderParallel(b *testing.B) {
+	provider := semanticid.NewMonotonicULIDProvider()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := provider.Generate(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -9,22 +9,45 @@ var _ json.Unmarshaler = &SemanticID{}
 
 // MarshalJSON implements the json.Marshaler interface for SemanticID
 func (sid SemanticID) MarshalJSON() ([]byte, error) {
+	return defaultCodec().EncodeJSON(sid)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for SemanticID
+func (sid *SemanticID) UnmarshalJSON(b []byte) error {
+	return defaultCodec().DecodeJSON(b, sid)
+}
+
+// EncodeJSON encodes the given SemanticID as a JSON string using
+// the Codec's settings. Nil SemanticIDs are encoded according to the
+// Codec's NilPolicy.
+func (c *Codec) EncodeJSON(sid SemanticID) ([]byte, error) {
 	if sid.IsNil() {
+		if c.nilAsEmptyString() {
+			return json.Marshal("")
+		}
+
 		return json.Marshal(nil)
 	}
 
-	str := sid.String()
+	str := c.String(sid)
 	return json.Marshal(str)
 }
 
-// UnmarshalJSON implements the json.Unmarshaler interface for SemanticID
-func (sid *SemanticID) UnmarshalJSON(b []byte) error {
-	var str string
+// DecodeJSON decodes a JSON string into the given SemanticID using
+// the Codec's settings. Null and empty strings are decoded into a nil
+// SemanticID.
+func (c *Codec) DecodeJSON(b []byte, sid *SemanticID) error {
+	var str *string
 	if err := json.Unmarshal(b, &str); err != nil {
 		return err
 	}
 
-	parsed, err := FromString(str)
+	if str == nil || *str == "" {
+		*sid = SemanticID{}
+		return nil
+	}
+
+	parsed, err := c.FromString(*str)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,104 @@
+package semanticid
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// visitFunc is called for every tagged SemanticID or *SemanticID value
+// found while walking a model.
+type visitFunc func(field reflect.Value, path, tag string) error
+
+// walkModel visits every SemanticID field with an `sid` tag in the given
+// model, following nested structs, slices, arrays and pointers. Tagged
+// slices and arrays of SemanticIDs are visited element by element.
+// Unexported fields are skipped, and pointers are only followed once.
+func walkModel(val reflect.Value, visit visitFunc) error {
+	w := modelWalker{visit: visit, visited: map[uintptr]bool{}}
+	return w.walk(val, "")
+}
+
+type modelWalker struct {
+	visit   visitFunc
+	visited map[uintptr]bool
+}
+
+func (w *modelWalker) walk(val reflect.Value, path string) error {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() || w.visited[val.Pointer()] {
+			return nil
+		}
+
+		w.visited[val.Pointer()] = true
+		return w.walk(val.Elem(), path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if err := w.walk(val.Index(i), indexPath(path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return w.walkStruct(val, path)
+	}
+
+	return nil
+}
+
+func (w *modelWalker) walkStruct(val reflect.Value, path string) error {
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		field := val.Field(i)
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+
+		tag, hasTag := f.Tag.Lookup("sid")
+		if !hasTag {
+			if err := w.walk(field, fieldPath); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if isSemanticIDType(f.Type) {
+			if err := w.visit(field, fieldPath, tag); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		kind := f.Type.Kind()
+		if (kind == reflect.Slice || kind == reflect.Array) && isSemanticIDType(f.Type.Elem()) {
+			for j := 0; j < field.Len(); j++ {
+				if err := w.visit(field.Index(j), indexPath(fieldPath, j), tag); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if err := w.walk(field, fieldPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isSemanticIDType(t reflect.Type) bool {
+	return t == rawType || t == pointerType
+}
+
+func indexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
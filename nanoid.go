@@ -0,0 +1,151 @@
+package semanticid
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+)
+
+const (
+	// DefaultNanoIDAlphabet is the URL-safe alphabet that is used by
+	// NanoID providers if no custom alphabet is specified.
+	DefaultNanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// DefaultNanoIDLength is the length of the IDs generated by NanoID
+	// providers if no custom length is specified.
+	DefaultNanoIDLength = 21
+)
+
+// NanoIDProvider generates short, random IDs from a configurable
+// alphabet, which makes them a good fit for public-facing resources.
+type NanoIDProvider struct {
+	alphabet string
+	length   int
+	entropy  io.Reader
+	allowed  [256]bool
+}
+
+var _ IDProvider = &NanoIDProvider{}
+
+// NanoIDOption configures a NanoIDProvider.
+type NanoIDOption func(*NanoIDProvider)
+
+// WithNanoIDAlphabet sets the characters that generated IDs consist of.
+// The alphabet has to contain between 2 and 128 unique ASCII characters.
+func WithNanoIDAlphabet(alphabet string) NanoIDOption {
+	return func(np *NanoIDProvider) {
+		np.alphabet = alphabet
+	}
+}
+
+// WithNanoIDLength sets the length of generated IDs.
+func WithNanoIDLength(length int) NanoIDOption {
+	return func(np *NanoIDProvider) {
+		np.length = length
+	}
+}
+
+// WithNanoIDEntropy sets the source of randomness for generated IDs.
+// The reader has to be safe for concurrent use if the provider is used
+// from multiple goroutines.
+func WithNanoIDEntropy(entropy io.Reader) NanoIDOption {
+	return func(np *NanoIDProvider) {
+		np.entropy = entropy
+	}
+}
+
+// NewNanoIDProvider creates a NanoID provider. An error is returned if
+// the alphabet is invalid or contains the separator, since IDs containing
+// the separator couldn't be parsed anymore.
+func NewNanoIDProvider(opts ...NanoIDOption) (*NanoIDProvider, error) {
+	np := &NanoIDProvider{
+		alphabet: DefaultNanoIDAlphabet,
+		length:   DefaultNanoIDLength,
+		entropy:  rand.Reader,
+	}
+
+	for _, opt := range opts {
+		opt(np)
+	}
+
+	if np.length <= 0 {
+		return nil, fmt.Errorf("NanoID length has to be positive, got %d", np.length)
+	}
+
+	if len(np.alphabet) < 2 || len(np.alphabet) > 128 {
+		return nil, fmt.Errorf(
+			"NanoID alphabet has to contain between 2 and 128 ASCII characters, got %d",
+			len(np.alphabet),
+		)
+	}
+
+	separator := defaultCodec().separator
+	if strings.Contains(np.alphabet, separator) {
+		return nil, &SemanticIDError{
+			errCode: errPartContainsSeparator,
+			message: fmt.Sprintf(
+				"NanoID alphabet `%s` can't contain the separator (%s)",
+				np.alphabet,
+				separator,
+			),
+		}
+	}
+
+	for i := 0; i < len(np.alphabet); i++ {
+		c := np.alphabet[i]
+		if c >= 0x80 {
+			return nil, fmt.Errorf("NanoID alphabet can only contain ASCII characters")
+		}
+
+		if np.allowed[c] {
+			return nil, fmt.Errorf("NanoID alphabet contains `%c` more than once", c)
+		}
+
+		np.allowed[c] = true
+	}
+
+	return np, nil
+}
+
+func (np *NanoIDProvider) Generate() (string, error) {
+	// NOTE: We only use the lowest bits of each random byte that are
+	// needed to index the alphabet, and discard bytes that are out of
+	// range. This avoids the bias a modulo would introduce.
+	mask := byte(1<<bits.Len(uint(len(np.alphabet)-1)) - 1)
+	step := 16*int(mask)*np.length/(10*len(np.alphabet)) + 1
+
+	result := make([]byte, 0, np.length)
+	buf := make([]byte, step)
+	for {
+		if _, err := io.ReadFull(np.entropy, buf); err != nil {
+			return "", err
+		}
+
+		for _, b := range buf {
+			idx := int(b & mask)
+			if idx >= len(np.alphabet) {
+				continue
+			}
+
+			result = append(result, np.alphabet[idx])
+			if len(result) == np.length {
+				return string(result), nil
+			}
+		}
+	}
+}
+
+func (np *NanoIDProvider) Validate(id string) error {
+	if len(id) != np.length {
+		return fmt.Errorf("NanoID %s has length %d, expected %d", id, len(id), np.length)
+	}
+
+	for i := 0; i < len(id); i++ {
+		if !np.allowed[id[i]] {
+			return fmt.Errorf("NanoID %s contains `%c`, which is not in the alphabet", id, id[i])
+		}
+	}
+
+	return nil
+}
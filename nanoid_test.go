@@ -0,0 +1,140 @@
+package semanticid_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("nanoid", func() {
+	Describe("Creating a NanoID provider", func() {
+		It("should reject alphabets containing the separator", func() {
+			_, err := semanticid.NewNanoIDProvider(semanticid.WithNanoIDAlphabet("abc.def"))
+			Expect(errors.Is(err, semanticid.ErrPartContainsSeparator)).To(BeTrue())
+		})
+
+		It("should reject invalid alphabets", func() {
+			_, err := semanticid.NewNanoIDProvider(semanticid.WithNanoIDAlphabet("a"))
+			Expect(err).NotTo(BeNil())
+
+			_, err = semanticid.NewNanoIDProvider(semanticid.WithNanoIDAlphabet("abca"))
+			Expect(err).NotTo(BeNil())
+
+			_, err = semanticid.NewNanoIDProvider(semanticid.WithNanoIDAlphabet("abcä"))
+			Expect(err).NotTo(BeNil())
+
+			_, err = semanticid.NewNanoIDProvider(semanticid.WithNanoIDAlphabet(strings.Repeat("a", 129)))
+			Expect(err).To(MatchError(ContainSubstring("between 2 and 128 ASCII characters")))
+		})
+
+		It("should reject invalid lengths", func() {
+			_, err := semanticid.NewNanoIDProvider(semanticid.WithNanoIDLength(0))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Using the NanoID provider", func() {
+		Context("to generate IDs", func() {
+			It("should generate IDs with the default alphabet and length", func() {
+				provider, err := semanticid.NewNanoIDProvider()
+				Expect(err).To(BeNil())
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+				Expect(id).To(HaveLen(semanticid.DefaultNanoIDLength))
+
+				for _, c := range id {
+					Expect(semanticid.DefaultNanoIDAlphabet).To(ContainSubstring(string(c)))
+				}
+			})
+
+			It("should generate IDs with a custom alphabet and length", func() {
+				provider, err := semanticid.NewNanoIDProvider(
+					semanticid.WithNanoIDAlphabet("abc"),
+					semanticid.WithNanoIDLength(8),
+				)
+				Expect(err).To(BeNil())
+
+				for i := 0; i < 100; i++ {
+					id, err := provider.Generate()
+					Expect(err).To(BeNil())
+					Expect(id).To(HaveLen(8))
+					Expect(strings.Trim(id, "abc")).To(BeEmpty())
+				}
+			})
+
+			It("should use the given entropy source", func() {
+				provider, err := semanticid.NewNanoIDProvider(
+					semanticid.WithNanoIDAlphabet("0123"),
+					semanticid.WithNanoIDLength(4),
+					semanticid.WithNanoIDEntropy(bytes.NewReader([]byte{0, 1, 2, 3, 4, 5, 6, 7})),
+				)
+				Expect(err).To(BeNil())
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+				Expect(id).To(Equal("0123"))
+			})
+
+			It("should generate unique IDs", func() {
+				provider, err := semanticid.NewNanoIDProvider()
+				Expect(err).To(BeNil())
+
+				seen := make(map[string]struct{})
+				for i := 0; i < 1000; i++ {
+					id, err := provider.Generate()
+					Expect(err).To(BeNil())
+					seen[id] = struct{}{}
+				}
+
+				Expect(seen).To(HaveLen(1000))
+			})
+
+			It("should be usable for semanticids", func() {
+				provider, err := semanticid.NewNanoIDProvider()
+				Expect(err).To(BeNil())
+
+				sid, err := semanticid.Builder().WithIDProvider(provider).Build()
+				Expect(err).To(BeNil())
+
+				parsed, err := semanticid.Builder().
+					WithIDProvider(provider).
+					FromString(sid.String()).
+					Build()
+				Expect(err).To(BeNil())
+				Expect(parsed).To(Equal(sid))
+			})
+		})
+
+		Context("to validate IDs", func() {
+			var provider *semanticid.NanoIDProvider
+
+			BeforeEach(func() {
+				var err error
+				provider, err = semanticid.NewNanoIDProvider(
+					semanticid.WithNanoIDAlphabet("abc"),
+					semanticid.WithNanoIDLength(4),
+				)
+				Expect(err).To(BeNil())
+			})
+
+			It("should accept valid IDs", func() {
+				Expect(provider.Validate("abca")).To(BeNil())
+			})
+
+			It("should reject IDs with the wrong length", func() {
+				Expect(provider.Validate("abc")).NotTo(BeNil())
+				Expect(provider.Validate("abcab")).NotTo(BeNil())
+			})
+
+			It("should reject IDs with characters outside of the alphabet", func() {
+				Expect(provider.Validate("abcd")).NotTo(BeNil())
+			})
+		})
+	})
+})
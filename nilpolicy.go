@@ -0,0 +1,49 @@
+package semanticid
+
+// NilPolicy determines how nil SemanticIDs are encoded. Regardless of
+// the policy, decoding always accepts both null and empty strings as a
+// nil SemanticID, so data written with a different policy can still be
+// read.
+//
+// Text and binary encodings have no way of representing null, so nil
+// SemanticIDs are always encoded as empty text or bytes there.
+type NilPolicy int
+
+const (
+	// NilAsNull encodes nil SemanticIDs as JSON null, BSON null and
+	// SQL NULL. This is the default.
+	NilAsNull NilPolicy = iota
+	// NilAsEmptyString encodes nil SemanticIDs as empty strings.
+	NilAsEmptyString
+	// NilOmit leaves out nil SemanticIDs wherever possible, which
+	// applies to BSON fields tagged with `omitempty`. Where a value has
+	// to be written, null is used.
+	NilOmit
+)
+
+// DefaultNilPolicy determines how nil SemanticIDs are encoded when
+// using the package-level settings.
+var DefaultNilPolicy = NilAsNull
+
+// IsZero reports whether the SemanticID is nil. This allows nil
+// SemanticIDs to be omitted by encoders that check for zero values.
+func (sID SemanticID) IsZero() bool {
+	return sID.IsNil()
+}
+
+// NilPolicy returns the policy the Codec uses for nil SemanticIDs.
+func (c *Codec) NilPolicy() NilPolicy {
+	return c.nilPolicy
+}
+
+// omitsNil reports whether nil SemanticIDs should be left out if the
+// encoder allows it.
+func (c *Codec) omitsNil() bool {
+	return c.nilPolicy == NilOmit
+}
+
+// nilAsEmptyString reports whether nil SemanticIDs should be encoded
+// as empty strings instead of null.
+func (c *Codec) nilAsEmptyString() bool {
+	return c.nilPolicy == NilAsEmptyString
+}
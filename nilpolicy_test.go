@@ -0,0 +1,316 @@
+package semanticid_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("nil policy", func() {
+	type nilEncoding struct {
+		name   string
+		encode func(c *semanticid.Codec, sid semanticid.SemanticID) (interface{}, error)
+		decode func(c *semanticid.Codec, encoded interface{}) (semanticid.SemanticID, error)
+		// nil representations the decoder has to accept
+		nils []interface{}
+	}
+
+	bsonValue := func(c *semanticid.Codec, sid semanticid.SemanticID) (interface{}, error) {
+		m, err := bson.MarshalWithRegistry(c.BuildBSONRegistry(nil), bson.M{"id": sid})
+		if err != nil {
+			return nil, err
+		}
+
+		return bson.Raw(m).Lookup("id"), nil
+	}
+
+	decodeBSONValue := func(c *semanticid.Codec, encoded interface{}) (semanticid.SemanticID, error) {
+		var result struct {
+			ID semanticid.SemanticID `bson:"id"`
+		}
+
+		m, err := bson.Marshal(bson.M{"id": encoded})
+		if err != nil {
+			return result.ID, err
+		}
+
+		err = bson.UnmarshalWithRegistry(c.BuildBSONRegistry(nil), m, &result)
+		return result.ID, err
+	}
+
+	encodings := []nilEncoding{
+		{
+			name: "json",
+			encode: func(c *semanticid.Codec, sid semanticid.SemanticID) (interface{}, error) {
+				b, err := c.EncodeJSON(sid)
+				return string(b), err
+			},
+			decode: func(c *semanticid.Codec, encoded interface{}) (semanticid.SemanticID, error) {
+				var result semanticid.SemanticID
+				err := c.DecodeJSON([]byte(encoded.(string)), &result)
+				return result, err
+			},
+			nils: []interface{}{"null", `""`},
+		},
+		{
+			name:   "bson",
+			encode: bsonValue,
+			decode: func(c *semanticid.Codec, encoded interface{}) (semanticid.SemanticID, error) {
+				return decodeBSONValue(c, encoded)
+			},
+			nils: []interface{}{nil, "", primitive.Undefined{}},
+		},
+		{
+			name: "bson pointer",
+			encode: func(c *semanticid.Codec, sid semanticid.SemanticID) (interface{}, error) {
+				m, err := bson.MarshalWithRegistry(c.BuildBSONRegistry(nil), bson.M{"id": &sid})
+				if err != nil {
+					return nil, err
+				}
+
+				return bson.Raw(m).Lookup("id"), nil
+			},
+			decode: func(c *semanticid.Codec, encoded interface{}) (semanticid.SemanticID, error) {
+				var result struct {
+					ID *semanticid.SemanticID `bson:"id"`
+				}
+
+				m, err := bson.Marshal(bson.M{"id": encoded})
+				if err != nil {
+					return semanticid.SemanticID{}, err
+				}
+
+				if err := bson.UnmarshalWithRegistry(c.BuildBSONRegistry(nil), m, &result); err != nil {
+					return semanticid.SemanticID{}, err
+				}
+
+				if result.ID == nil {
+					return semanticid.SemanticID{}, nil
+				}
+
+				return *result.ID, nil
+			},
+			nils: []interface{}{nil, "", primitive.Undefined{}},
+		},
+		{
+			name: "sql",
+			encode: func(c *semanticid.Codec, sid semanticid.SemanticID) (interface{}, error) {
+				return c.EncodeSQL(sid)
+			},
+			decode: func(c *semanticid.Codec, encoded interface{}) (semanticid.SemanticID, error) {
+				var result semanticid.SemanticID
+				err := c.DecodeSQL(encoded, &result)
+				return result, err
+			},
+			nils: []interface{}{nil, ""},
+		},
+		{
+			name: "text",
+			encode: func(c *semanticid.Codec, sid semanticid.SemanticID) (interface{}, error) {
+				b, err := c.EncodeText(sid)
+				return string(b), err
+			},
+			decode: func(c *semanticid.Codec, encoded interface{}) (semanticid.SemanticID, error) {
+				var result semanticid.SemanticID
+				err := c.DecodeText([]byte(encoded.(string)), &result)
+				return result, err
+			},
+			nils: []interface{}{""},
+		},
+		{
+			name: "binary",
+			encode: func(c *semanticid.Codec, sid semanticid.SemanticID) (interface{}, error) {
+				b, err := c.EncodeBinary(sid)
+				return string(b), err
+			},
+			decode: func(c *semanticid.Codec, encoded interface{}) (semanticid.SemanticID, error) {
+				var result semanticid.SemanticID
+				err := c.DecodeBinary([]byte(encoded.(string)), &result)
+				return result, err
+			},
+			nils: []interface{}{""},
+		},
+	}
+
+	bsonNull := bson.RawValue{Type: bsontype.Null}
+	emptyBSONString := func() interface{} {
+		m, _ := bson.Marshal(bson.M{"id": ""})
+		return bson.Raw(m).Lookup("id")
+	}()
+
+	expected := map[semanticid.NilPolicy]map[string]interface{}{
+		semanticid.NilAsNull: {
+			"json":         "null",
+			"bson":         bsonNull,
+			"bson pointer": bsonNull,
+			"sql":          nil,
+			"text":         "",
+			"binary":       "",
+		},
+		semanticid.NilAsEmptyString: {
+			"json":         `""`,
+			"bson":         emptyBSONString,
+			"bson pointer": emptyBSONString,
+			"sql":          "",
+			"text":         "",
+			"binary":       "",
+		},
+		semanticid.NilOmit: {
+			"json":         "null",
+			"bson":         bsonNull,
+			"bson pointer": bsonNull,
+			"sql":          nil,
+			"text":         "",
+			"binary":       "",
+		},
+	}
+
+	Describe("Encoding nil semanticids", func() {
+		It("should follow the policy in every codec", func() {
+			for policy, representations := range expected {
+				codec := semanticid.NewCodec(semanticid.WithNilPolicy(policy))
+				Expect(codec.NilPolicy()).To(Equal(policy))
+
+				for _, enc := range encodings {
+					encoded, err := enc.encode(codec, semanticid.SemanticID{})
+					Expect(err).To(BeNil(), enc.name)
+
+					if raw, ok := encoded.(bson.RawValue); ok {
+						want := representations[enc.name].(bson.RawValue)
+						Expect(raw.Type).To(Equal(want.Type), enc.name)
+						Expect(raw.Value).To(Equal(want.Value), enc.name)
+						continue
+					}
+
+					if representations[enc.name] == nil {
+						Expect(encoded).To(BeNil(), enc.name)
+						continue
+					}
+
+					Expect(encoded).To(Equal(representations[enc.name]), enc.name)
+				}
+			}
+		})
+
+		It("should round-trip in every codec with every policy", func() {
+			for policy := range expected {
+				codec := semanticid.NewCodec(semanticid.WithNilPolicy(policy))
+				sid := semanticid.Must(codec.NewDefault())
+
+				for _, enc := range encodings {
+					for _, original := range []semanticid.SemanticID{sid, {}} {
+						encoded, err := enc.encode(codec, original)
+						Expect(err).To(BeNil(), enc.name)
+
+						if raw, ok := encoded.(bson.RawValue); ok && raw.Type == bsontype.Null {
+							encoded = nil
+						} else if ok {
+							encoded = raw.StringValue()
+						}
+
+						result, err := enc.decode(codec, encoded)
+						Expect(err).To(BeNil(), enc.name)
+						Expect(result).To(Equal(original), enc.name)
+					}
+				}
+			}
+		})
+	})
+
+	Describe("Decoding nil semanticids", func() {
+		It("should accept every nil representation regardless of policy", func() {
+			for policy := range expected {
+				codec := semanticid.NewCodec(semanticid.WithNilPolicy(policy))
+
+				for _, enc := range encodings {
+					for _, representation := range enc.nils {
+						result, err := enc.decode(codec, representation)
+						Expect(err).To(BeNil(), enc.name)
+						Expect(result.IsNil()).To(BeTrue(), enc.name)
+					}
+				}
+			}
+		})
+
+		It("should keep reading after undefined bson values", func() {
+			m, err := bson.Marshal(bson.D{{Key: "id", Value: primitive.Undefined{}}, {Key: "x", Value: 5}})
+			Expect(err).To(BeNil())
+
+			result := struct {
+				ID semanticid.SemanticID `bson:"id"`
+				X  int                   `bson:"x"`
+			}{ID: semanticid.Must(semanticid.NewDefault())}
+			Expect(bson.UnmarshalWithRegistry(semanticid.BuildBSONRegistry(nil), m, &result)).To(BeNil())
+			Expect(result.ID.IsNil()).To(BeTrue())
+			Expect(result.X).To(Equal(5))
+		})
+
+		It("should decode json null into the zero value", func() {
+			result := semanticid.Must(semanticid.NewDefault())
+			Expect(json.Unmarshal([]byte("null"), &result)).To(BeNil())
+			Expect(result.IsNil()).To(BeTrue())
+		})
+	})
+
+	Describe("Omitting nil semanticids", func() {
+		type model struct {
+			ID  semanticid.SemanticID  `bson:"id,omitempty"`
+			Ptr *semanticid.SemanticID `bson:"ptr,omitempty"`
+		}
+
+		It("should omit tagged bson fields with the omit policy", func() {
+			codec := semanticid.NewCodec(semanticid.WithNilPolicy(semanticid.NilOmit))
+			m, err := bson.MarshalWithRegistry(codec.BuildBSONRegistry(nil), model{Ptr: &semanticid.SemanticID{}})
+			Expect(err).To(BeNil())
+
+			var raw map[string]interface{}
+			Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+			Expect(raw).To(BeEmpty())
+		})
+
+		It("should keep tagged bson fields with other policies", func() {
+			codec := semanticid.NewCodec(semanticid.WithNilPolicy(semanticid.NilAsNull))
+			m, err := bson.MarshalWithRegistry(codec.BuildBSONRegistry(nil), model{Ptr: &semanticid.SemanticID{}})
+			Expect(err).To(BeNil())
+
+			var raw map[string]interface{}
+			Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+			Expect(raw).To(HaveKeyWithValue("id", BeNil()))
+			Expect(raw).To(HaveKeyWithValue("ptr", BeNil()))
+		})
+
+		It("should always omit nil pointers", func() {
+			m, err := bson.MarshalWithRegistry(semanticid.BuildBSONRegistry(nil), model{ID: semanticid.Must(semanticid.NewDefault())})
+			Expect(err).To(BeNil())
+
+			var raw map[string]interface{}
+			Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+			Expect(raw).To(HaveKey("id"))
+			Expect(raw).NotTo(HaveKey("ptr"))
+		})
+	})
+
+	Describe("Using the package-level policy", func() {
+		AfterEach(func() {
+			semanticid.DefaultNilPolicy = semanticid.NilAsNull
+		})
+
+		It("should be used by the marshal methods", func() {
+			semanticid.DefaultNilPolicy = semanticid.NilAsEmptyString
+
+			m, err := json.Marshal(semanticid.SemanticID{})
+			Expect(err).To(BeNil())
+			Expect(string(m)).To(Equal(`""`))
+
+			value, err := semanticid.SemanticID{}.Value()
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(""))
+		})
+	})
+})
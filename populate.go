@@ -0,0 +1,102 @@
+package semanticid
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PopulateIDs walks the given model recursively, including nested
+// structs, slices, arrays and pointers, and assigns a new SemanticID to
+// every nil SemanticID field that has an `sid` tag. Nil pointers to
+// SemanticIDs are allocated, and tagged slices and arrays of SemanticIDs
+// are filled element by element. Fields that are already set are
+// skipped, as are fields with reference tags like `accounts.users`, since
+// those point to other models. The model has to be a pointer to a struct.
+// The returned paths name the assigned fields, e.g. `Items[0].ID`.
+//
+// All tags are checked and all IDs are generated before anything is
+// assigned, so the model is left unchanged if an error is returned.
+func PopulateIDs(model interface{}) ([]string, error) {
+	return defaultCodec().PopulateIDs(model)
+}
+
+// PopulateIDs works like the package-level PopulateIDs, using the
+// Codec's settings.
+func (c *Codec) PopulateIDs(model interface{}) ([]string, error) {
+	val := reflect.ValueOf(model)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("PopulateIDs needs a pointer to a struct, got %T", model)
+	}
+
+	var pending []pendingID
+	err := walkModel(val, func(field reflect.Value, path, rawTag string) error {
+		if isReferenceTag(rawTag, c.separator) {
+			return nil
+		}
+
+		if field.Type() == pointerType {
+			if ptr := field.Interface().(*SemanticID); ptr != nil && !ptr.IsNil() {
+				return nil
+			}
+		} else if !field.Interface().(SemanticID).IsNil() {
+			return nil
+		}
+
+		tag, err := parseTag(rawTag, c.separator)
+		if err != nil {
+			return fmt.Errorf("Field `%s`: %w", path, err)
+		}
+
+		sid, err := c.newForTag(tag)
+		if err != nil {
+			return fmt.Errorf("Field `%s`: %w", path, err)
+		}
+
+		pending = append(pending, pendingID{field: field, path: path, sid: sid})
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var assigned []string
+	for _, p := range pending {
+		if p.assign() {
+			assigned = append(assigned, p.path)
+		}
+	}
+
+	return assigned, nil
+}
+
+// pendingID is a SemanticID that PopulateIDs is going to assign.
+type pendingID struct {
+	field reflect.Value
+	path  string
+	sid   SemanticID
+}
+
+// assign writes the SemanticID to the field. Pointers to SemanticIDs can
+// be shared between fields, so they are checked again and only assigned
+// once.
+func (p pendingID) assign() bool {
+	if p.field.Type() != pointerType {
+		*p.field.Addr().Interface().(*SemanticID) = p.sid
+		return true
+	}
+
+	ptr := p.field.Addr().Interface().(**SemanticID)
+	if *ptr == nil {
+		sid := p.sid
+		*ptr = &sid
+		return true
+	}
+
+	if !(*ptr).IsNil() {
+		return false
+	}
+
+	**ptr = p.sid
+	return true
+}
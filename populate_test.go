@@ -0,0 +1,176 @@
+package semanticid_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+type PopulateItem struct {
+	ID   semanticid.SemanticID `sid:"items"`
+	Name string
+}
+
+type PopulateOrder struct {
+	ID       semanticid.SemanticID `sid:"orders,ns=shop"`
+	Owner    *TaggedModel
+	Ref      *semanticid.SemanticID `sid:"refs"`
+	Items    []PopulateItem
+	Pointers []*PopulateItem
+	Untagged semanticid.SemanticID
+	parent   *PopulateOrder
+}
+
+var _ = Describe("populate", func() {
+	Describe("Populating semanticids in models", func() {
+		It("should assign nested, sliced and pointed to fields", func() {
+			order := PopulateOrder{
+				Owner:    &TaggedModel{},
+				Items:    []PopulateItem{{Name: "a"}, {Name: "b"}},
+				Pointers: []*PopulateItem{nil, {Name: "c"}},
+			}
+
+			assigned, err := semanticid.PopulateIDs(&order)
+			Expect(err).To(BeNil())
+			Expect(assigned).To(Equal([]string{
+				"ID",
+				"Owner.ID",
+				"Ref",
+				"Items[0].ID",
+				"Items[1].ID",
+				"Pointers[1].ID",
+			}))
+
+			Expect(order.ID.Namespace).To(Equal("shop"))
+			Expect(order.ID.Collection).To(Equal("orders"))
+			Expect(order.Owner.ID.Namespace).To(Equal("accounts"))
+			Expect(semanticid.NewUUIDProvider().Validate(order.Owner.ID.ID)).To(BeNil())
+			Expect(order.Ref).NotTo(BeNil())
+			Expect(order.Ref.Collection).To(Equal("refs"))
+			Expect(order.Items[0].ID.Collection).To(Equal("items"))
+			Expect(order.Items[0].ID).NotTo(Equal(order.Items[1].ID))
+			Expect(order.Pointers[0]).To(BeNil())
+			Expect(order.Pointers[1].ID.IsNil()).To(BeFalse())
+			Expect(order.Untagged.IsNil()).To(BeTrue())
+		})
+
+		It("should skip fields that are already set", func() {
+			existing := semanticid.Must(semanticid.New("shop", "orders"))
+			order := PopulateOrder{
+				ID:    existing,
+				Ref:   &existing,
+				Items: []PopulateItem{{ID: existing}, {}},
+			}
+
+			assigned, err := semanticid.PopulateIDs(&order)
+			Expect(err).To(BeNil())
+			Expect(assigned).To(Equal([]string{"Items[1].ID"}))
+			Expect(order.ID).To(Equal(existing))
+			Expect(*order.Ref).To(Equal(existing))
+			Expect(order.Items[0].ID).To(Equal(existing))
+		})
+
+		It("should fill tagged slices element by element", func() {
+			existing := semanticid.Must(semanticid.New("shop", "tags"))
+			model := struct {
+				Tags     []semanticid.SemanticID   `sid:"tags"`
+				Pointers [2]*semanticid.SemanticID `sid:"tags"`
+				hidden   semanticid.SemanticID     `sid:"hidden"`
+			}{Tags: []semanticid.SemanticID{{}, existing}}
+
+			assigned, err := semanticid.PopulateIDs(&model)
+			Expect(err).To(BeNil())
+			Expect(assigned).To(Equal([]string{"Tags[0]", "Pointers[0]", "Pointers[1]"}))
+			Expect(model.Tags[0].Collection).To(Equal("tags"))
+			Expect(model.Tags[1]).To(Equal(existing))
+			Expect(model.Pointers[1].Collection).To(Equal("tags"))
+			Expect(model.hidden.IsNil()).To(BeTrue())
+		})
+
+		It("should skip references to other models", func() {
+			model := ReferencingModel{Items: []ReferencedItem{{}}}
+
+			assigned, err := semanticid.PopulateIDs(&model)
+			Expect(err).To(BeNil())
+			Expect(assigned).To(Equal([]string{"ID", "Items[0].ProductID"}))
+			Expect(model.OwnerID.IsNil()).To(BeTrue())
+			Expect(model.Reviewer).To(BeNil())
+			Expect(semanticid.ValidateModel(model)).To(BeNil())
+		})
+
+		It("should not loop on cyclic pointers", func() {
+			order := &PopulateOrder{}
+			order.parent = order
+			order.Pointers = []*PopulateItem{{}}
+			order.Pointers = append(order.Pointers, order.Pointers[0])
+
+			assigned, err := semanticid.PopulateIDs(order)
+			Expect(err).To(BeNil())
+			Expect(assigned).To(Equal([]string{"ID", "Ref", "Pointers[0].ID"}))
+		})
+
+		It("should use the codec's settings", func() {
+			registry := semanticid.NewProviderRegistry()
+			registry.Register("unknown", &TestProvider{})
+
+			codec := semanticid.NewCodec(semanticid.WithProviderRegistry(registry))
+			model := UnknownProviderModel{}
+			_, err := codec.PopulateIDs(&model)
+			Expect(err).To(BeNil())
+			Expect(model.ID.ID).To(Equal("1234"))
+		})
+
+		It("should report the field that couldn't be populated", func() {
+			_, err := semanticid.PopulateIDs(&struct{ Owner UnknownProviderModel }{})
+			Expect(errors.Is(err, semanticid.ErrIDProvider)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("Owner.ID"))
+
+			_, err = semanticid.PopulateIDs(&struct {
+				ID semanticid.SemanticID `sid:"a,foo=bar"`
+			}{})
+			Expect(errors.Is(err, semanticid.ErrInvalidTag)).To(BeTrue())
+		})
+
+		It("should leave the model unchanged on errors", func() {
+			model := struct {
+				ID    semanticid.SemanticID `sid:"things"`
+				Items []PopulateItem
+				Ref   *semanticid.SemanticID `sid:"refs"`
+				Bad   semanticid.SemanticID  `sid:"x,bogus=1"`
+			}{Items: []PopulateItem{{}}}
+
+			assigned, err := semanticid.PopulateIDs(&model)
+			Expect(errors.Is(err, semanticid.ErrInvalidTag)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("Bad"))
+			Expect(assigned).To(BeNil())
+			Expect(model.ID.IsNil()).To(BeTrue())
+			Expect(model.Items[0].ID.IsNil()).To(BeTrue())
+			Expect(model.Ref).To(BeNil())
+		})
+
+		It("should assign shared pointers once", func() {
+			shared := &semanticid.SemanticID{}
+			model := struct {
+				A *semanticid.SemanticID `sid:"things"`
+				B *semanticid.SemanticID `sid:"things"`
+			}{A: shared, B: shared}
+
+			assigned, err := semanticid.PopulateIDs(&model)
+			Expect(err).To(BeNil())
+			Expect(assigned).To(Equal([]string{"A"}))
+			Expect(shared.IsNil()).To(BeFalse())
+			Expect(model.B).To(BeIdenticalTo(shared))
+		})
+
+		It("should only accept pointers to structs", func() {
+			_, err := semanticid.PopulateIDs(PopulateOrder{})
+			Expect(err).NotTo(BeNil())
+
+			_, err = semanticid.PopulateIDs((*PopulateOrder)(nil))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,187 @@
+package semanticid
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DefaultProviderRegistry is consulted whenever a SemanticID is created
+// or parsed without explicitly selecting an ID provider. If none of its
+// bindings match the SemanticID's namespace and collection,
+// DefaultIDProvider is used.
+var DefaultProviderRegistry = NewProviderRegistry()
+
+// ProviderRegistry holds named ID providers and binds them to namespace
+// and collection patterns, so that different parts of an application
+// can use different kinds of IDs. It is safe for concurrent use.
+type ProviderRegistry struct {
+	mu       sync.RWMutex
+	named    map[string]IDProvider
+	bindings []providerBinding
+}
+
+type providerBinding struct {
+	namespace  string
+	collection string
+	provider   IDProvider
+}
+
+// NewProviderRegistry creates a registry without any bindings. The
+// builtin providers are registered as `ulid`, `uuid`, `uuidv7`
+// and `ksuid`.
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{
+		named: map[string]IDProvider{
+			"ulid":   NewULIDProvider(),
+			"uuid":   NewUUIDProvider(),
+			"uuidv7": NewUUIDv7Provider(),
+			"ksuid":  NewKSUIDProvider(),
+		},
+	}
+}
+
+// Register makes the provider available under the given name,
+// replacing any provider that was registered with the same name.
+func (r *ProviderRegistry) Register(name string, idp IDProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.named[name] = idp
+}
+
+// Provider returns the provider registered under the given name.
+func (r *ProviderRegistry) Provider(name string) (IDProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	idp, ok := r.named[name]
+	return idp, ok
+}
+
+// Bind selects the provider for all SemanticIDs matching the given
+// pattern. Patterns use the same syntax as the `sid` validation tag:
+// `namespace.collection`, where either part can be `*`, or just
+// `collection`, which matches that collection in any namespace. Patterns
+// are split using the default Codec's separator, use Codec.Bind for
+// registries of Codecs with a different separator.
+func (r *ProviderRegistry) Bind(pattern string, idp IDProvider) error {
+	return r.bind(pattern, defaultCodec().separator, idp)
+}
+
+func (r *ProviderRegistry) bind(pattern, separator string, idp IDProvider) error {
+	namespace, collection, err := parseSIDPattern(pattern, separator)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.bindings = append(r.bindings, providerBinding{
+		namespace:  namespace,
+		collection: collection,
+		provider:   idp,
+	})
+
+	return nil
+}
+
+// BindNamed works like Bind, but selects a provider that has
+// previously been registered under the given name.
+func (r *ProviderRegistry) BindNamed(pattern, name string) error {
+	return r.bindNamed(pattern, defaultCodec().separator, name)
+}
+
+func (r *ProviderRegistry) bindNamed(pattern, separator, name string) error {
+	idp, ok := r.Provider(name)
+	if !ok {
+		return fmt.Errorf("No ID provider registered as `%s`", name)
+	}
+
+	return r.bind(pattern, separator, idp)
+}
+
+// bindTag binds the provider to exactly the given namespace and
+// collection, so that SemanticIDs created for a `provider` tag option
+// are parsed with the same provider. It reports false if they are
+// already bound to a different provider.
+func (r *ProviderRegistry) bindTag(namespace, collection string, idp IDProvider) bool {
+	r.mu.RLock()
+	bound, ok := r.exactBinding(namespace, collection)
+	r.mu.RUnlock()
+
+	if ok {
+		return sameProvider(bound, idp)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if bound, ok := r.exactBinding(namespace, collection); ok {
+		return sameProvider(bound, idp)
+	}
+
+	r.bindings = append(r.bindings, providerBinding{
+		namespace:  namespace,
+		collection: collection,
+		provider:   idp,
+	})
+
+	return true
+}
+
+// exactBinding returns the provider that was bound last to exactly the
+// given namespace and collection. The caller must hold the lock.
+func (r *ProviderRegistry) exactBinding(namespace, collection string) (IDProvider, bool) {
+	for i := len(r.bindings) - 1; i >= 0; i-- {
+		b := r.bindings[i]
+		if b.namespace == namespace && b.collection == collection {
+			return b.provider, true
+		}
+	}
+
+	return nil, false
+}
+
+// sameProvider compares providers without panicking on providers that
+// aren't comparable, which are never considered the same.
+func sameProvider(a, b IDProvider) bool {
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// Lookup returns the provider bound to the given namespace and
+// collection. If multiple bindings match, bindings for a specific
+// collection take precedence over bindings for a specific namespace,
+// and those take precedence over wildcard bindings. Among equally
+// specific bindings, the one that was bound last wins.
+func (r *ProviderRegistry) Lookup(namespace, collection string) (IDProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result IDProvider
+	best := -1
+	for _, b := range r.bindings {
+		sid := SemanticID{Namespace: namespace, Collection: collection}
+		if !validateSIDPrefix(sid, b.namespace, b.collection) {
+			continue
+		}
+
+		score := 0
+		if b.collection != "*" {
+			score += 2
+		}
+
+		if b.namespace != "*" {
+			score++
+		}
+
+		if score >= best {
+			best = score
+			result = b.provider
+		}
+	}
+
+	return result, result != nil
+}
@@ -0,0 +1,171 @@
+package semanticid_test
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("registry", func() {
+	var (
+		registry     *semanticid.ProviderRegistry
+		testProvider semanticid.IDProvider
+		uuidProvider semanticid.IDProvider
+	)
+
+	BeforeEach(func() {
+		registry = semanticid.NewProviderRegistry()
+		testProvider = &TestProvider{}
+		uuidProvider = semanticid.NewUUIDProvider()
+	})
+
+	Describe("Using named providers", func() {
+		It("should include the builtin providers", func() {
+			for _, name := range []string{"ulid", "uuid", "uuidv7", "ksuid"} {
+				_, ok := registry.Provider(name)
+				Expect(ok).To(BeTrue())
+			}
+		})
+
+		It("should return registered providers", func() {
+			registry.Register("test", testProvider)
+
+			idp, ok := registry.Provider("test")
+			Expect(ok).To(BeTrue())
+			Expect(idp).To(BeIdenticalTo(testProvider))
+		})
+
+		It("should bind providers by name", func() {
+			registry.Register("test", testProvider)
+			Expect(registry.BindNamed("events.*", "test")).To(BeNil())
+
+			idp, ok := registry.Lookup("events", "clicks")
+			Expect(ok).To(BeTrue())
+			Expect(idp).To(BeIdenticalTo(testProvider))
+		})
+
+		It("should reject unknown names", func() {
+			Expect(registry.BindNamed("events.*", "unknown")).NotTo(BeNil())
+		})
+	})
+
+	Describe("Looking up providers", func() {
+		It("should not return a provider without bindings", func() {
+			_, ok := registry.Lookup("any", "any")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should match namespace and collection patterns", func() {
+			Expect(registry.Bind("events.clicks", testProvider)).To(BeNil())
+
+			idp, ok := registry.Lookup("events", "clicks")
+			Expect(ok).To(BeTrue())
+			Expect(idp).To(BeIdenticalTo(testProvider))
+
+			_, ok = registry.Lookup("events", "views")
+			Expect(ok).To(BeFalse())
+
+			_, ok = registry.Lookup("other", "clicks")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should match collections in any namespace with the short form", func() {
+			Expect(registry.Bind("legacy", uuidProvider)).To(BeNil())
+
+			idp, ok := registry.Lookup("any", "legacy")
+			Expect(ok).To(BeTrue())
+			Expect(idp).To(BeIdenticalTo(uuidProvider))
+		})
+
+		It("should prefer more specific bindings", func() {
+			ulidProvider := semanticid.NewULIDProvider()
+			Expect(registry.Bind("*.*", ulidProvider)).To(BeNil())
+			Expect(registry.Bind("events.*", testProvider)).To(BeNil())
+			Expect(registry.Bind("*.legacy", uuidProvider)).To(BeNil())
+
+			idp, _ := registry.Lookup("users", "users")
+			Expect(idp).To(BeIdenticalTo(ulidProvider))
+
+			idp, _ = registry.Lookup("events", "clicks")
+			Expect(idp).To(BeIdenticalTo(testProvider))
+
+			idp, _ = registry.Lookup("events", "legacy")
+			Expect(idp).To(BeIdenticalTo(uuidProvider))
+		})
+
+		It("should prefer later bindings that are equally specific", func() {
+			Expect(registry.Bind("events.*", uuidProvider)).To(BeNil())
+			Expect(registry.Bind("events.*", testProvider)).To(BeNil())
+
+			idp, _ := registry.Lookup("events", "clicks")
+			Expect(idp).To(BeIdenticalTo(testProvider))
+		})
+
+		It("should reject invalid patterns", func() {
+			Expect(registry.Bind("", testProvider)).NotTo(BeNil())
+			Expect(registry.Bind("a.b.c", testProvider)).NotTo(BeNil())
+		})
+	})
+
+	Describe("Using the default registry", func() {
+		var previous *semanticid.ProviderRegistry
+
+		BeforeEach(func() {
+			previous = semanticid.DefaultProviderRegistry
+			semanticid.DefaultProviderRegistry = registry
+
+			Expect(registry.Bind("legacy.*", uuidProvider)).To(BeNil())
+			Expect(registry.Bind("test.*", testProvider)).To(BeNil())
+		})
+
+		AfterEach(func() {
+			semanticid.DefaultProviderRegistry = previous
+		})
+
+		It("should generate IDs with the bound provider", func() {
+			sid, err := semanticid.New("legacy", "users")
+			Expect(err).To(BeNil())
+
+			_, err = uuid.FromString(sid.ID)
+			Expect(err).To(BeNil())
+
+			sid, err = semanticid.New("test", "users")
+			Expect(err).To(BeNil())
+			Expect(sid.ID).To(Equal("1234"))
+		})
+
+		It("should fall back to the default provider", func() {
+			sid, err := semanticid.New("other", "users")
+			Expect(err).To(BeNil())
+			Expect(semanticid.DefaultIDProvider.Validate(sid.ID)).To(BeNil())
+		})
+
+		It("should validate parsed IDs with the bound provider", func() {
+			_, err := semanticid.FromString("legacy.users.6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+			Expect(err).To(BeNil())
+
+			_, err = semanticid.FromString("test.users.1234")
+			Expect(err).To(BeNil())
+
+			_, err = semanticid.FromString("other.users.6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+			Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+		})
+
+		It("should be used by the builder unless a provider is selected", func() {
+			sid, err := semanticid.Builder().WithNamespace("test").Build()
+			Expect(err).To(BeNil())
+			Expect(sid.ID).To(Equal("1234"))
+
+			sid, err = semanticid.Builder().
+				WithNamespace("test").
+				WithIDProvider(uuidProvider).
+				Build()
+			Expect(err).To(BeNil())
+			Expect(sid.ID).NotTo(Equal("1234"))
+		})
+	})
+})
@@ -2,8 +2,7 @@ package semanticid
 
 import (
 	"fmt"
-	"reflect"
-	"strings"
+	"time"
 )
 
 // DefaultNamespace is the namespace that will be used if
@@ -22,8 +21,10 @@ var DefaultCollection = "collection"
 var Separator = "."
 
 // DefaultIDProvider determines the provider that will be used to
-// generate and validate IDs. You can either set this or use the
-// builder to select the provider on an individual basis.
+// generate and validate IDs. You can either set this, bind providers
+// to specific namespaces and collections using the
+// DefaultProviderRegistry, or use the builder to select the provider
+// on an individual basis.
 var DefaultIDProvider IDProvider = NewULIDProvider()
 
 var empty = SemanticID{}
@@ -34,6 +35,9 @@ const (
 	errInvalidID
 	errPartContainsSeparator
 	errEmpty
+	errNoTimestamp
+	errWrongKind
+	errInvalidTag
 )
 
 var (
@@ -42,6 +46,9 @@ var (
 	ErrInvalid               = &SemanticIDError{errInvalidSID, ""}
 	ErrInvalidIDPart         = &SemanticIDError{errInvalidID, ""}
 	ErrPartContainsSeparator = &SemanticIDError{errPartContainsSeparator, ""}
+	ErrNoTimestamp           = &SemanticIDError{errNoTimestamp, ""}
+	ErrWrongKind             = &SemanticIDError{errWrongKind, ""}
+	ErrInvalidTag            = &SemanticIDError{errInvalidTag, ""}
 )
 
 // A SemanticID is a unique identifier for an entity that consists
@@ -73,30 +80,30 @@ func (err *SemanticIDError) Is(target error) bool {
 // New creates a unique SemanticID with the given namespace,
 // collection and the global separator (`.` by default).
 func New(namespace, collection string) (SemanticID, error) {
-	return newWithParams(namespace, collection, DefaultIDProvider)
+	return defaultCodec().New(namespace, collection)
 }
 
 // NewWithCollection creates a unique SemanticID with the given
 // collection and the default namespace.
 func NewWithCollection(collection string) (SemanticID, error) {
-	return New(DefaultNamespace, collection)
+	return defaultCodec().NewWithCollection(collection)
 }
 
 // NewWithNamespace creates a unique SemanticID with the given
 // namespace and the default collection.
 func NewWithNamespace(namespace string) (SemanticID, error) {
-	return New(namespace, DefaultCollection)
+	return defaultCodec().NewWithNamespace(namespace)
 }
 
 // NewDefault creates a unique SemanticID with the default namespace
 // and collection.
 func NewDefault() (SemanticID, error) {
-	return New(DefaultNamespace, DefaultCollection)
+	return defaultCodec().NewDefault()
 }
 
 // FromString attempts to parse a given string into a SemanticID.
 func FromString(s string) (SemanticID, error) {
-	return fromStringWithParams(s, DefaultIDProvider, true)
+	return defaultCodec().FromString(s)
 }
 
 // FromStrings attempts to parse a given list of strings into a
@@ -104,104 +111,13 @@ func FromString(s string) (SemanticID, error) {
 // conversion that errors, which means that a list that returns
 // an error is not guaranteed to only contain that one error.
 func FromStrings(s []string) ([]SemanticID, error) {
-	result := make([]SemanticID, len(s))
-	for i, id := range s {
-		sid, err := FromString(id)
-		if err != nil {
-			return nil, err
-		}
-
-		result[i] = sid
-	}
-
-	return result, nil
+	return defaultCodec().FromStrings(s)
 }
 
 // ToStrings converts a list of semanticids to their
 // string representation.
 func ToStrings(s []SemanticID) []string {
-	result := make([]string, len(s))
-	for i, id := range s {
-		result[i] = id.String()
-	}
-
-	return result
-}
-
-func newWithParams(namespace, collection string, idp IDProvider) (SemanticID, error) {
-	id, err := idp.Generate()
-	if err != nil {
-		return empty, &SemanticIDError{
-			errCode: errIDProviderError,
-			message: err.Error(),
-		}
-	}
-
-	if strings.Contains(namespace, Separator) {
-		return empty, &SemanticIDError{
-			errCode: errPartContainsSeparator,
-			message: fmt.Sprintf(
-				"Namespace `%s` can't contain the separator (%s)",
-				namespace,
-				Separator,
-			),
-		}
-	}
-
-	if strings.Contains(collection, Separator) {
-		return empty, &SemanticIDError{
-			errCode: errPartContainsSeparator,
-			message: fmt.Sprintf(
-				"Collection `%s` can't contain the separator (%s)",
-				collection,
-				Separator,
-			),
-		}
-	}
-
-	return SemanticID{
-		Namespace:  namespace,
-		Collection: collection,
-		ID:         id,
-	}, nil
-}
-
-func fromStringWithParams(s string, idp IDProvider, validate bool) (SemanticID, error) {
-	if s == "" {
-		return SemanticID{}, &SemanticIDError{errEmpty, "The given string was empty"}
-	}
-
-	parts := strings.SplitN(s, Separator, 3)
-
-	// SplitN(_, 3) guarantees at most len 3 for the
-	// result, so we only need to check if there aren't enough
-	if len(parts) < 3 {
-		return empty, &SemanticIDError{
-			errCode: errInvalidSID,
-			message: fmt.Sprintf("%s is not a valid semantic id", s),
-		}
-	}
-
-	namespace := parts[0]
-	collection := parts[1]
-	id := parts[2]
-
-	if validate {
-		// check if the ID part is valid
-		err := idp.Validate(id)
-		if err != nil {
-			return empty, &SemanticIDError{
-				errCode: errInvalidID,
-				message: fmt.Sprintf("The UUID section for %s is invalid", s),
-			}
-		}
-	}
-
-	return SemanticID{
-		Namespace:  namespace,
-		Collection: collection,
-		ID:         id,
-	}, nil
+	return defaultCodec().ToStrings(s)
 }
 
 // IsNil checks whether or not the SemanticID has any of its part
@@ -212,11 +128,14 @@ func (sID SemanticID) IsNil() bool {
 
 // String outputs a string representation of the SemanticID
 func (sID SemanticID) String() string {
-	if sID.IsNil() {
-		return ""
-	}
+	return defaultCodec().String(sID)
+}
 
-	return strings.Join([]string{sID.Namespace, sID.Collection, sID.ID}, Separator)
+// Timestamp returns the time at which the SemanticID was created, if its
+// ID provider implements TimestampProvider. The provider is selected
+// the same way as when parsing the SemanticID.
+func (sID SemanticID) Timestamp() (time.Time, error) {
+	return defaultCodec().Timestamp(sID)
 }
 
 // Is checks the identity of a SemanticID, given by its Namespace and Collection.
@@ -249,45 +168,17 @@ func CollectionForModel(model interface{}) (string, error) {
 // CollectionForModelField returns the collection defined in the `sid` tag
 // on the given field.
 func CollectionForModelField(model interface{}, field string) (string, error) {
-	var t reflect.Type
-	kind := reflect.ValueOf(model).Kind()
-	if kind == reflect.Struct {
-		t = reflect.TypeOf(model)
-	} else if kind == reflect.Ptr {
-		t = reflect.Indirect(reflect.ValueOf(model)).Type()
-	}
-
-	f, ok := t.FieldByName(field)
-	if !ok {
-		return "", fmt.Errorf("Field `%s` not found on model", field)
-	}
-
-	tag := f.Tag.Get("sid")
-	if tag == "" {
-		return "", fmt.Errorf("Field `%s` did not include an sid tag", field)
-	}
-
-	if strings.Contains(tag, Separator) {
-		return "", &SemanticIDError{
-			errCode: errPartContainsSeparator,
-			message: fmt.Sprintf(
-				"Collection `%s` can't contain the separator (`%s`)",
-				tag,
-				Separator,
-			),
-		}
+	tag, err := TagForModelField(model, field)
+	if err != nil {
+		return "", err
 	}
 
-	return tag, nil
+	return tag.Collection, nil
 }
 
-// NewForModel creates a unique SemanticID using the collection defined
-// in the `sid` tag in the given model.
+// NewForModel creates a unique SemanticID using the collection, namespace
+// and provider defined in the `sid` tag in the given model. See Tag for
+// the supported options.
 func NewForModel(model interface{}) (SemanticID, error) {
-	collection, err := CollectionForModel(model)
-	if err != nil {
-		return empty, err
-	}
-
-	return NewWithCollection(collection)
+	return defaultCodec().NewForModel(model)
 }
@@ -1,6 +1,9 @@
 package semanticid_test
 
 import (
+	"errors"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -122,6 +125,56 @@ var _ = Describe("semanticid", func() {
 		})
 	})
 
+	Describe("Reading the timestamp of a semanticid", func() {
+		It("should return the creation time for time-based providers", func() {
+			before := time.Now().Truncate(time.Millisecond)
+			sid, err := semanticid.NewDefault()
+			Expect(err).To(BeNil())
+			after := time.Now()
+
+			result, err := sid.Timestamp()
+			Expect(err).To(BeNil())
+			Expect(result).To(BeTemporally(">=", before))
+			Expect(result).To(BeTemporally("<=", after))
+		})
+
+		It("should use the provider selected for the namespace and collection", func() {
+			t := time.UnixMilli(1600000000123)
+			registry := semanticid.NewProviderRegistry()
+			Expect(registry.Bind("events.*", semanticid.NewUUIDv7Provider(
+				semanticid.WithUUIDv7Clock(func() time.Time { return t }),
+			))).To(BeNil())
+
+			codec := semanticid.NewCodec(semanticid.WithProviderRegistry(registry))
+			sid, err := codec.New("events", "clicks")
+			Expect(err).To(BeNil())
+
+			result, err := codec.Timestamp(sid)
+			Expect(err).To(BeNil())
+			Expect(result.Equal(t)).To(BeTrue())
+		})
+
+		It("should fail for providers without timestamps", func() {
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(&TestProvider{}))
+			sid, err := codec.NewDefault()
+			Expect(err).To(BeNil())
+
+			_, err = codec.Timestamp(sid)
+			Expect(errors.Is(err, semanticid.ErrNoTimestamp)).To(BeTrue())
+		})
+
+		It("should fail for invalid IDs", func() {
+			sid := semanticid.SemanticID{Namespace: "a", Collection: "b", ID: "1234"}
+			_, err := sid.Timestamp()
+			Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+		})
+
+		It("should fail for nil IDs", func() {
+			_, err := semanticid.SemanticID{}.Timestamp()
+			Expect(errors.Is(err, semanticid.ErrEmpty)).To(BeTrue())
+		})
+	})
+
 	Describe("Using the collection struct tag", func() {
 		Context("with the default model field", func() {
 			It("should return the correct collection for a model value", func() {
@@ -153,7 +206,13 @@ var _ = Describe("semanticid", func() {
 		Context("with an invalid tag", func() {
 			It("should return an error", func() {
 				_, err := semanticid.CollectionForModelField(TestModel{}, "InvalidTag")
-				Expect(err).NotTo(BeNil())
+				Expect(errors.Is(err, semanticid.ErrPartContainsSeparator)).To(BeTrue())
+				Expect(errors.Is(err, semanticid.ErrInvalidTag)).To(BeTrue())
+
+				_, err = semanticid.NewForModel(struct {
+					ID semanticid.SemanticID `sid:"test.models"`
+				}{})
+				Expect(errors.Is(err, semanticid.ErrPartContainsSeparator)).To(BeTrue())
 			})
 		})
 	})
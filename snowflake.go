@@ -0,0 +1,415 @@
+package semanticid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultSnowflakeEpoch is the epoch that will be used by snowflake
+// providers if no custom epoch is specified.
+var DefaultSnowflakeEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeEncoding determines how snowflake IDs are rendered
+// in the ID part of a SemanticID.
+type SnowflakeEncoding int
+
+const (
+	// SnowflakeDecimal renders IDs as decimal numbers, without padding.
+	SnowflakeDecimal SnowflakeEncoding = iota
+	// SnowflakeBase62 renders IDs as 11 character base62 strings, which
+	// sort in the same order as the numbers they represent.
+	SnowflakeBase62
+)
+
+const (
+	defaultSnowflakeNodeBits     = 10
+	defaultSnowflakeSequenceBits = 12
+
+	// maxSnowflakeNodeAndSequenceBits makes sure that at least 41 bits,
+	// or roughly 69 years, remain for the timestamp.
+	maxSnowflakeNodeAndSequenceBits = 22
+
+	base62Alphabet        = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	snowflakeBase62Length = 11
+)
+
+// SnowflakeProvider generates 64 bit, time-ordered IDs that fit into
+// a signed 64 bit integer. Each ID consists of the milliseconds since
+// the provider's epoch, the ID of the node that generated it and a
+// sequence number that is incremented for IDs generated within the
+// same millisecond. It is safe for concurrent use, but every provider
+// generating IDs at the same time has to use a distinct node ID.
+type SnowflakeProvider struct {
+	mu           sync.Mutex
+	clock        func() time.Time
+	epoch        time.Time
+	nodeID       int64
+	nodeBits     uint
+	sequenceBits uint
+	encoding     SnowflakeEncoding
+
+	lastMS   int64
+	sequence int64
+}
+
+var _ IDProvider = &SnowflakeProvider{}
+var _ TimestampProvider = &SnowflakeProvider{}
+var _ IDComparator = &SnowflakeProvider{}
+var _ TimeRangeProvider = &SnowflakeProvider{}
+var _ StringTimeOrderer = &SnowflakeProvider{}
+var _ BinaryIDProvider = &SnowflakeProvider{}
+
+// SnowflakeOption configures a SnowflakeProvider.
+type SnowflakeOption func(*SnowflakeProvider)
+
+// WithSnowflakeClock sets the function that is used to determine the
+// timestamp of generated IDs.
+func WithSnowflakeClock(clock func() time.Time) SnowflakeOption {
+	return func(sp *SnowflakeProvider) {
+		sp.clock = clock
+	}
+}
+
+// WithSnowflakeEpoch sets the point in time that timestamps are
+// counted from. Defaults to DefaultSnowflakeEpoch.
+func WithSnowflakeEpoch(epoch time.Time) SnowflakeOption {
+	return func(sp *SnowflakeProvider) {
+		sp.epoch = epoch
+	}
+}
+
+// WithSnowflakeNodeBits sets the number of bits used for the node ID.
+// Defaults to 10.
+func WithSnowflakeNodeBits(bits uint) SnowflakeOption {
+	return func(sp *SnowflakeProvider) {
+		sp.nodeBits = bits
+	}
+}
+
+// WithSnowflakeSequenceBits sets the number of bits used for the
+// sequence number, which limits how many IDs can be generated per
+// millisecond. Defaults to 12.
+func WithSnowflakeSequenceBits(bits uint) SnowflakeOption {
+	return func(sp *SnowflakeProvider) {
+		sp.sequenceBits = bits
+	}
+}
+
+// WithSnowflakeEncoding sets how IDs are rendered. Defaults to
+// SnowflakeDecimal.
+func WithSnowflakeEncoding(encoding SnowflakeEncoding) SnowflakeOption {
+	return func(sp *SnowflakeProvider) {
+		sp.encoding = encoding
+	}
+}
+
+// NewSnowflakeProvider creates a snowflake provider for the given node.
+// An error is returned if the node ID doesn't fit into the configured
+// number of node bits, or if the node and sequence bits don't leave
+// enough room for the timestamp.
+func NewSnowflakeProvider(nodeID int64, opts ...SnowflakeOption) (*SnowflakeProvider, error) {
+	sp := &SnowflakeProvider{
+		clock:        time.Now,
+		epoch:        DefaultSnowflakeEpoch,
+		nodeID:       nodeID,
+		nodeBits:     defaultSnowflakeNodeBits,
+		sequenceBits: defaultSnowflakeSequenceBits,
+		encoding:     SnowflakeDecimal,
+		lastMS:       -1,
+	}
+
+	for _, opt := range opts {
+		opt(sp)
+	}
+
+	if sp.nodeBits+sp.sequenceBits > maxSnowflakeNodeAndSequenceBits {
+		return nil, fmt.Errorf(
+			"node and sequence bits can't exceed %d bits in total",
+			maxSnowflakeNodeAndSequenceBits,
+		)
+	}
+
+	if nodeID < 0 || nodeID >= 1<<sp.nodeBits {
+		return nil, fmt.Errorf(
+			"node ID %d doesn't fit into %d bits",
+			nodeID,
+			sp.nodeBits,
+		)
+	}
+
+	if sp.encoding != SnowflakeDecimal && sp.encoding != SnowflakeBase62 {
+		return nil, fmt.Errorf("unknown snowflake encoding %d", sp.encoding)
+	}
+
+	return sp, nil
+}
+
+func (sp *SnowflakeProvider) Generate() (string, error) {
+	id, err := sp.next()
+	if err != nil {
+		return "", err
+	}
+
+	return sp.FormatInt64(id), nil
+}
+
+func (sp *SnowflakeProvider) Validate(id string) error {
+	_, err := sp.Int64(id)
+	return err
+}
+
+// Int64 returns the numeric value of the given snowflake ID, e.g.
+// for storing it in an integer column.
+func (sp *SnowflakeProvider) Int64(id string) (int64, error) {
+	var (
+		result int64
+		err    error
+	)
+
+	switch sp.encoding {
+	case SnowflakeBase62:
+		result, err = decodeSnowflakeBase62(id)
+	default:
+		result, err = strconv.ParseInt(id, 10, 64)
+		if err == nil && strconv.FormatInt(result, 10) != id {
+			err = fmt.Errorf("%s is not a canonical decimal number", id)
+		}
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if result < 0 {
+		return 0, fmt.Errorf("snowflake ID %s can't be negative", id)
+	}
+
+	return result, nil
+}
+
+// FormatInt64 renders the numeric value of a snowflake ID using the
+// provider's encoding.
+func (sp *SnowflakeProvider) FormatInt64(id int64) string {
+	if sp.encoding == SnowflakeBase62 {
+		return encodeSnowflakeBase62(id)
+	}
+
+	return strconv.FormatInt(id, 10)
+}
+
+// Timestamp returns the time that is embedded in the given snowflake ID.
+func (sp *SnowflakeProvider) Timestamp(id string) (time.Time, error) {
+	value, err := sp.Int64(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ms := value >> (sp.nodeBits + sp.sequenceBits)
+	return sp.epoch.Add(time.Duration(ms) * time.Millisecond), nil
+}
+
+// Compare orders snowflake IDs by their numeric value, which sorts them
+// by the time they were created at. Invalid IDs sort after valid ones.
+func (sp *SnowflakeProvider) Compare(a, b string) int {
+	valueA, errA := sp.Int64(a)
+	valueB, errB := sp.Int64(b)
+	if result, ok := compareInvalid(a, b, errA, errB); ok {
+		return result
+	}
+
+	switch {
+	case valueA < valueB:
+		return -1
+	case valueA > valueB:
+		return 1
+	}
+
+	return 0
+}
+
+// StringsSortByTime reports whether IDs sort by time as strings, which
+// is only the case for SnowflakeBase62. Decimal IDs aren't padded, so
+// `999` sorts after `1000`.
+func (sp *SnowflakeProvider) StringsSortByTime() bool {
+	return sp.encoding == SnowflakeBase62
+}
+
+// MinID returns the smallest snowflake ID for the given millisecond.
+// Note that decimal IDs only sort correctly as numbers, not as strings.
+func (sp *SnowflakeProvider) MinID(t time.Time) (string, error) {
+	ms, err := sp.since(t)
+	if err != nil {
+		return "", err
+	}
+
+	return sp.FormatInt64(ms << (sp.nodeBits + sp.sequenceBits)), nil
+}
+
+// MaxID returns the largest snowflake ID for the given millisecond.
+// Note that decimal IDs only sort correctly as numbers, not as strings.
+func (sp *SnowflakeProvider) MaxID(t time.Time) (string, error) {
+	ms, err := sp.since(t)
+	if err != nil {
+		return "", err
+	}
+
+	shift := sp.nodeBits + sp.sequenceBits
+	return sp.FormatInt64(ms<<shift | (1<<shift - 1)), nil
+}
+
+// IDToBytes returns the 8 byte big-endian binary form of the given
+// snowflake ID.
+func (sp *SnowflakeProvider) IDToBytes(id string) ([]byte, error) {
+	value, err := sp.Int64(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 8)
+	binary.BigEndian.PutUint64(result, uint64(value))
+	return result, nil
+}
+
+// IDFromBytes converts the 8 byte binary form of a snowflake ID to a
+// string, using the provider's encoding.
+func (sp *SnowflakeProvider) IDFromBytes(b []byte) (string, error) {
+	if len(b) != 8 {
+		return "", fmt.Errorf("expected 8 bytes for a snowflake ID, got %d", len(b))
+	}
+
+	value := int64(binary.BigEndian.Uint64(b))
+	if value < 0 {
+		return "", fmt.Errorf("snowflake ID %d can't be negative", value)
+	}
+
+	return sp.FormatInt64(value), nil
+}
+
+func (sp *SnowflakeProvider) next() (int64, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	now, err := sp.now()
+	if err != nil {
+		return 0, err
+	}
+
+	// NOTE: If the clock moves backwards, we keep using the last
+	// timestamp so that IDs never decrease. As long as there are
+	// sequence numbers left, this is indistinguishable from generating
+	// many IDs within a single millisecond.
+	behind := now < sp.lastMS
+	if behind {
+		now = sp.lastMS
+	}
+
+	if now == sp.lastMS {
+		sp.sequence = (sp.sequence + 1) & (1<<sp.sequenceBits - 1)
+		if sp.sequence == 0 {
+			if behind {
+				return 0, fmt.Errorf(
+					"clock moved backwards and the sequence for millisecond %d is exhausted",
+					now,
+				)
+			}
+
+			if now, err = sp.waitAfter(now); err != nil {
+				return 0, err
+			}
+		}
+	} else {
+		sp.sequence = 0
+	}
+
+	sp.lastMS = now
+
+	return now<<(sp.nodeBits+sp.sequenceBits) |
+		sp.nodeID<<sp.sequenceBits |
+		sp.sequence, nil
+}
+
+// now returns the milliseconds since the provider's epoch.
+func (sp *SnowflakeProvider) now() (int64, error) {
+	return sp.since(sp.clock())
+}
+
+// since returns the milliseconds between the provider's epoch and
+// the given time.
+func (sp *SnowflakeProvider) since(t time.Time) (int64, error) {
+	ms := t.Sub(sp.epoch).Milliseconds()
+	if ms < 0 {
+		return 0, fmt.Errorf("time %s is before the snowflake epoch %s", t, sp.epoch)
+	}
+
+	if uint64(ms) >= uint64(1)<<(63-sp.nodeBits-sp.sequenceBits) {
+		return 0, fmt.Errorf("time %s is too far after the snowflake epoch %s", t, sp.epoch)
+	}
+
+	return ms, nil
+}
+
+// waitAfter blocks until the clock has moved past the given millisecond,
+// which is used once the sequence for a millisecond is exhausted.
+func (sp *SnowflakeProvider) waitAfter(ms int64) (int64, error) {
+	for {
+		now, err := sp.now()
+		if err != nil {
+			return 0, err
+		}
+
+		if now > ms {
+			return now, nil
+		}
+
+		time.Sleep(100 * time.Microsecond)
+	}
+}
+
+func encodeSnowflakeBase62(id int64) string {
+	var result [snowflakeBase62Length]byte
+	for i := snowflakeBase62Length - 1; i >= 0; i-- {
+		result[i] = base62Alphabet[id%62]
+		id /= 62
+	}
+
+	return string(result[:])
+}
+
+func decodeSnowflakeBase62(id string) (int64, error) {
+	if len(id) != snowflakeBase62Length {
+		return 0, fmt.Errorf("%s is not a valid base62 snowflake ID", id)
+	}
+
+	var result uint64
+	for i := 0; i < len(id); i++ {
+		digit := base62Value(id[i])
+		if digit < 0 {
+			return 0, fmt.Errorf("%s is not a valid base62 snowflake ID", id)
+		}
+
+		if result > (math.MaxInt64-uint64(digit))/62 {
+			return 0, fmt.Errorf("%s overflows a 64 bit integer", id)
+		}
+
+		result = result*62 + uint64(digit)
+	}
+
+	return int64(result), nil
+}
+
+func base62Value(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 36
+	}
+
+	return -1
+}
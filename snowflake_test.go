@@ -0,0 +1,288 @@
+package semanticid_test
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("snowflake", func() {
+	var (
+		now   time.Time
+		clock func() time.Time
+	)
+
+	BeforeEach(func() {
+		now = semanticid.DefaultSnowflakeEpoch.Add(time.Hour)
+		clock = func() time.Time { return now }
+	})
+
+	Describe("Creating a snowflake provider", func() {
+		It("should reject node IDs that don't fit into the node bits", func() {
+			_, err := semanticid.NewSnowflakeProvider(1024)
+			Expect(err).NotTo(BeNil())
+
+			_, err = semanticid.NewSnowflakeProvider(-1)
+			Expect(err).NotTo(BeNil())
+
+			_, err = semanticid.NewSnowflakeProvider(4, semanticid.WithSnowflakeNodeBits(2))
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should reject bit configurations that leave no room for the timestamp", func() {
+			_, err := semanticid.NewSnowflakeProvider(
+				1,
+				semanticid.WithSnowflakeNodeBits(12),
+				semanticid.WithSnowflakeSequenceBits(12),
+			)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("Using the snowflake provider", func() {
+		Context("to generate IDs", func() {
+			It("should generate valid decimal IDs", func() {
+				provider, err := semanticid.NewSnowflakeProvider(1)
+				Expect(err).To(BeNil())
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				value, err := strconv.ParseInt(id, 10, 64)
+				Expect(err).To(BeNil())
+				Expect(value).To(BeNumerically(">", 0))
+				Expect(provider.Validate(id)).To(BeNil())
+			})
+
+			It("should lay out timestamp, node and sequence", func() {
+				provider, err := semanticid.NewSnowflakeProvider(
+					5,
+					semanticid.WithSnowflakeClock(clock),
+				)
+				Expect(err).To(BeNil())
+
+				first, err := provider.Generate()
+				Expect(err).To(BeNil())
+				second, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				ms := time.Hour.Milliseconds()
+				Expect(provider.Int64(first)).To(Equal(ms<<22 | 5<<12))
+				Expect(provider.Int64(second)).To(Equal(ms<<22 | 5<<12 | 1))
+			})
+
+			It("should respect a custom epoch", func() {
+				epoch := time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC)
+				provider, err := semanticid.NewSnowflakeProvider(
+					1,
+					semanticid.WithSnowflakeEpoch(epoch),
+					semanticid.WithSnowflakeClock(func() time.Time { return epoch.Add(time.Second) }),
+				)
+				Expect(err).To(BeNil())
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+				Expect(provider.Int64(id)).To(Equal(int64(1000)<<22 | 1<<12))
+			})
+
+			It("should reject times before the epoch", func() {
+				provider, err := semanticid.NewSnowflakeProvider(
+					1,
+					semanticid.WithSnowflakeClock(func() time.Time {
+						return semanticid.DefaultSnowflakeEpoch.Add(-time.Second)
+					}),
+				)
+				Expect(err).To(BeNil())
+
+				_, err = provider.Generate()
+				Expect(err).NotTo(BeNil())
+			})
+
+			It("should wait for the next millisecond once the sequence is exhausted", func() {
+				calls := 0
+				provider, err := semanticid.NewSnowflakeProvider(
+					1,
+					semanticid.WithSnowflakeSequenceBits(2),
+					semanticid.WithSnowflakeClock(func() time.Time {
+						calls++
+						if calls > 6 {
+							return now.Add(time.Millisecond)
+						}
+
+						return now
+					}),
+				)
+				Expect(err).To(BeNil())
+
+				ids := make([]string, 6)
+				for i := range ids {
+					ids[i], err = provider.Generate()
+					Expect(err).To(BeNil())
+				}
+
+				first, err := provider.Timestamp(ids[0])
+				Expect(err).To(BeNil())
+				last, err := provider.Timestamp(ids[5])
+				Expect(err).To(BeNil())
+				Expect(last.Sub(first)).To(Equal(time.Millisecond))
+
+				values := make([]int, len(ids))
+				for i, id := range ids {
+					value, err := provider.Int64(id)
+					Expect(err).To(BeNil())
+					values[i] = int(value)
+				}
+
+				Expect(sort.IntsAreSorted(values)).To(BeTrue())
+			})
+
+			It("should keep increasing when the clock moves backwards", func() {
+				provider, err := semanticid.NewSnowflakeProvider(1, semanticid.WithSnowflakeClock(clock))
+				Expect(err).To(BeNil())
+
+				first, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				now = now.Add(-time.Second)
+				second, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				firstValue, err := provider.Int64(first)
+				Expect(err).To(BeNil())
+				secondValue, err := provider.Int64(second)
+				Expect(err).To(BeNil())
+				Expect(secondValue).To(BeNumerically(">", firstValue))
+			})
+
+			It("should fail when the clock is behind and the sequence is exhausted", func() {
+				provider, err := semanticid.NewSnowflakeProvider(
+					1,
+					semanticid.WithSnowflakeSequenceBits(1),
+					semanticid.WithSnowflakeClock(clock),
+				)
+				Expect(err).To(BeNil())
+
+				_, err = provider.Generate()
+				Expect(err).To(BeNil())
+
+				now = now.Add(-time.Second)
+				_, err = provider.Generate()
+				Expect(err).To(BeNil())
+
+				_, err = provider.Generate()
+				Expect(err).NotTo(BeNil())
+			})
+
+			It("should generate unique IDs from many goroutines", func() {
+				provider, err := semanticid.NewSnowflakeProvider(1)
+				Expect(err).To(BeNil())
+
+				const workers = 8
+				const perWorker = 1000
+
+				var (
+					mu   sync.Mutex
+					seen = make(map[string]struct{}, workers*perWorker)
+					wg   sync.WaitGroup
+				)
+
+				for w := 0; w < workers; w++ {
+					wg.Add(1)
+					go func() {
+						defer GinkgoRecover()
+						defer wg.Done()
+
+						for i := 0; i < perWorker; i++ {
+							id, err := provider.Generate()
+							Expect(err).To(BeNil())
+
+							mu.Lock()
+							seen[id] = struct{}{}
+							mu.Unlock()
+						}
+					}()
+				}
+				wg.Wait()
+
+				Expect(seen).To(HaveLen(workers * perWorker))
+			})
+		})
+
+		Context("with base62 encoding", func() {
+			It("should generate fixed-length IDs that sort in generation order", func() {
+				provider, err := semanticid.NewSnowflakeProvider(
+					1,
+					semanticid.WithSnowflakeEncoding(semanticid.SnowflakeBase62),
+				)
+				Expect(err).To(BeNil())
+
+				ids := make([]string, 100)
+				for i := range ids {
+					ids[i], err = provider.Generate()
+					Expect(err).To(BeNil())
+					Expect(ids[i]).To(HaveLen(11))
+					Expect(provider.Validate(ids[i])).To(BeNil())
+				}
+
+				Expect(sort.StringsAreSorted(ids)).To(BeTrue())
+			})
+
+			It("should round-trip numeric values", func() {
+				provider, err := semanticid.NewSnowflakeProvider(
+					1,
+					semanticid.WithSnowflakeEncoding(semanticid.SnowflakeBase62),
+				)
+				Expect(err).To(BeNil())
+
+				for _, value := range []int64{0, 1, 61, 62, 1 << 40, 1<<63 - 1} {
+					Expect(provider.Int64(provider.FormatInt64(value))).To(Equal(value))
+				}
+			})
+
+			It("should reject invalid IDs", func() {
+				provider, err := semanticid.NewSnowflakeProvider(
+					1,
+					semanticid.WithSnowflakeEncoding(semanticid.SnowflakeBase62),
+				)
+				Expect(err).To(BeNil())
+
+				Expect(provider.Validate("1234")).NotTo(BeNil())
+				Expect(provider.Validate("0000000000-")).NotTo(BeNil())
+				Expect(provider.Validate("zzzzzzzzzzz")).NotTo(BeNil())
+			})
+		})
+
+		Context("to validate decimal IDs", func() {
+			It("should reject invalid IDs", func() {
+				provider, err := semanticid.NewSnowflakeProvider(1)
+				Expect(err).To(BeNil())
+
+				Expect(provider.Validate("abc")).NotTo(BeNil())
+				Expect(provider.Validate("-1234")).NotTo(BeNil())
+				Expect(provider.Validate("01234")).NotTo(BeNil())
+				Expect(provider.Validate("+1234")).NotTo(BeNil())
+				Expect(provider.Validate("99999999999999999999")).NotTo(BeNil())
+			})
+		})
+
+		Context("to extract timestamps", func() {
+			It("should return the embedded time", func() {
+				provider, err := semanticid.NewSnowflakeProvider(1, semanticid.WithSnowflakeClock(clock))
+				Expect(err).To(BeNil())
+
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+
+				result, err := provider.Timestamp(id)
+				Expect(err).To(BeNil())
+				Expect(result.Equal(now)).To(BeTrue())
+			})
+		})
+	})
+})
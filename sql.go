@@ -0,0 +1,102 @@
+package semanticid
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+var _ sql.Scanner = &SemanticID{}
+var _ driver.Valuer = SemanticID{}
+
+var _ sql.Scanner = &NullSemanticID{}
+var _ driver.Valuer = NullSemanticID{}
+
+// Value implements the driver.Valuer interface for SemanticID.
+// Nil SemanticIDs are stored according to the DefaultNilPolicy.
+func (sid SemanticID) Value() (driver.Value, error) {
+	return defaultCodec().EncodeSQL(sid)
+}
+
+// Scan implements the sql.Scanner interface for SemanticID.
+// NULL and empty strings are scanned into a nil SemanticID.
+func (sid *SemanticID) Scan(src interface{}) error {
+	return defaultCodec().DecodeSQL(src, sid)
+}
+
+// NullSemanticID represents a SemanticID that may be NULL. It can be used
+// as a scan destination, similar to sql.NullString.
+type NullSemanticID struct {
+	SemanticID SemanticID
+	Valid      bool
+}
+
+// Value implements the driver.Valuer interface for NullSemanticID.
+func (n NullSemanticID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+
+	return n.SemanticID.Value()
+}
+
+// Scan implements the sql.Scanner interface for NullSemanticID. Empty
+// strings are nil SemanticIDs as well, so they aren't valid either.
+func (n *NullSemanticID) Scan(src interface{}) error {
+	if src == nil {
+		n.SemanticID, n.Valid = SemanticID{}, false
+		return nil
+	}
+
+	if err := n.SemanticID.Scan(src); err != nil {
+		return err
+	}
+
+	n.Valid = !n.SemanticID.IsNil()
+	return nil
+}
+
+// EncodeSQL converts the given SemanticID into a database value using
+// the Codec's settings. Nil SemanticIDs are stored as an empty string
+// with NilAsEmptyString, and as NULL otherwise.
+func (c *Codec) EncodeSQL(sid SemanticID) (driver.Value, error) {
+	if sid.IsNil() {
+		if c.nilAsEmptyString() {
+			return "", nil
+		}
+
+		return nil, nil
+	}
+
+	return c.String(sid), nil
+}
+
+// DecodeSQL parses a database value into the given SemanticID using
+// the Codec's settings.
+func (c *Codec) DecodeSQL(src interface{}, sid *SemanticID) error {
+	var str string
+	switch value := src.(type) {
+	case nil:
+		*sid = SemanticID{}
+		return nil
+	case string:
+		str = value
+	case []byte:
+		str = string(value)
+	default:
+		return fmt.Errorf("cannot scan %T into a semanticid", src)
+	}
+
+	if str == "" {
+		*sid = SemanticID{}
+		return nil
+	}
+
+	parsed, err := c.FromString(str)
+	if err != nil {
+		return err
+	}
+
+	*sid = parsed
+	return nil
+}
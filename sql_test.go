@@ -0,0 +1,107 @@
+package semanticid_test
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("sql", func() {
+	var sid semanticid.SemanticID
+
+	BeforeEach(func() {
+		sid = semanticid.Must(semanticid.NewDefault())
+	})
+
+	Describe("Converting semanticids to database values", func() {
+		It("should store valid semanticids as strings", func() {
+			value, err := sid.Value()
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(driver.Value(sid.String())))
+		})
+
+		It("should store nil semanticids as NULL", func() {
+			value, err := semanticid.SemanticID{}.Value()
+			Expect(err).To(BeNil())
+			Expect(value).To(BeNil())
+		})
+
+		It("should store invalid nullable semanticids as NULL", func() {
+			value, err := semanticid.NullSemanticID{SemanticID: sid}.Value()
+			Expect(err).To(BeNil())
+			Expect(value).To(BeNil())
+
+			value, err = semanticid.NullSemanticID{SemanticID: sid, Valid: true}.Value()
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(driver.Value(sid.String())))
+		})
+	})
+
+	Describe("Scanning semanticids from database values", func() {
+		It("should parse strings and byte slices", func() {
+			var result semanticid.SemanticID
+			Expect(result.Scan(sid.String())).To(BeNil())
+			Expect(result).To(Equal(sid))
+
+			result = semanticid.SemanticID{}
+			Expect(result.Scan([]byte(sid.String()))).To(BeNil())
+			Expect(result).To(Equal(sid))
+		})
+
+		It("should scan NULL and empty strings into a nil semanticid", func() {
+			result := sid
+			Expect(result.Scan(nil)).To(BeNil())
+			Expect(result.IsNil()).To(BeTrue())
+
+			result = sid
+			Expect(result.Scan("")).To(BeNil())
+			Expect(result.IsNil()).To(BeTrue())
+		})
+
+		It("should validate scanned values", func() {
+			var result semanticid.SemanticID
+			err := result.Scan("a.b.1234")
+			Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+		})
+
+		It("should reject unsupported types", func() {
+			var result semanticid.SemanticID
+			Expect(result.Scan(1234)).NotTo(BeNil())
+		})
+
+		It("should track validity of nullable semanticids", func() {
+			var result semanticid.NullSemanticID
+			Expect(result.Scan(sid.String())).To(BeNil())
+			Expect(result.Valid).To(BeTrue())
+			Expect(result.SemanticID).To(Equal(sid))
+
+			Expect(result.Scan(nil)).To(BeNil())
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.SemanticID.IsNil()).To(BeTrue())
+
+			Expect(result.Scan(sid.String())).To(BeNil())
+			Expect(result.Scan("")).To(BeNil())
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.SemanticID.IsNil()).To(BeTrue())
+		})
+	})
+
+	Describe("Using a codec", func() {
+		It("should use the codec's separator", func() {
+			codec := semanticid.NewCodec(semanticid.WithSeparator(":"))
+			sid := semanticid.Must(codec.NewDefault())
+
+			value, err := codec.EncodeSQL(sid)
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(driver.Value(codec.String(sid))))
+
+			var result semanticid.SemanticID
+			Expect(codec.DecodeSQL(value, &result)).To(BeNil())
+			Expect(result).To(Equal(sid))
+		})
+	})
+})
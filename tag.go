@@ -0,0 +1,250 @@
+package semanticid
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Options that can be set in an `sid` struct tag.
+const (
+	tagOptionNamespace = "ns"
+	tagOptionProvider  = "provider"
+)
+
+// Tag is the parsed form of an `sid` struct tag. Tags start with the
+// collection, optionally followed by comma-separated options:
+//
+//	ID semanticid.SemanticID `sid:"users,ns=accounts,provider=uuid"`
+//
+// The `ns` option sets the namespace, which defaults to the default
+// namespace. The `provider` option selects an ID provider by the name it
+// was registered under in the provider registry. Creating an ID for the
+// tag binds that provider to the tag's namespace and collection in the
+// registry, so that the IDs can be parsed again. If they are already
+// bound to a different provider, creating the ID fails.
+type Tag struct {
+	Collection string
+	Namespace  string
+	Provider   string
+}
+
+// ParseTag parses the value of an `sid` struct tag, using the default
+// Codec's separator. Errors point to the malformed part of the
+// tag.
+func ParseTag(tag string) (Tag, error) {
+	return parseTag(tag, defaultCodec().separator)
+}
+
+// TagForModel returns the parsed `sid` tag on the `ID` field of the
+// passed struct.
+func TagForModel(model interface{}) (Tag, error) {
+	return TagForModelField(model, "ID")
+}
+
+// TagForModelField returns the parsed `sid` tag on the given field.
+func TagForModelField(model interface{}, field string) (Tag, error) {
+	return modelTag(model, field, defaultCodec().separator)
+}
+
+func modelTag(model interface{}, field, separator string) (Tag, error) {
+	var t reflect.Type
+	kind := reflect.ValueOf(model).Kind()
+	if kind == reflect.Struct {
+		t = reflect.TypeOf(model)
+	} else if kind == reflect.Ptr {
+		t = reflect.Indirect(reflect.ValueOf(model)).Type()
+	}
+
+	f, ok := t.FieldByName(field)
+	if !ok {
+		return Tag{}, fmt.Errorf("Field `%s` not found on model", field)
+	}
+
+	tag := f.Tag.Get("sid")
+	if tag == "" {
+		return Tag{}, fmt.Errorf("Field `%s` did not include an sid tag", field)
+	}
+
+	return parseTag(tag, separator)
+}
+
+func parseTag(tag, separator string) (Tag, error) {
+	parts := strings.Split(tag, ",")
+	result := Tag{Collection: parts[0]}
+
+	if result.Collection == "" {
+		return Tag{}, tagError(tag, 0, "the collection is missing")
+	}
+
+	if i := strings.Index(result.Collection, separator); i >= 0 {
+		return Tag{}, tagSeparatorError(tag, i, fmt.Sprintf(
+			"collection `%s` can't contain the separator (`%s`)",
+			result.Collection,
+			separator,
+		))
+	}
+
+	offset := len(parts[0]) + 1
+	seen := map[string]bool{}
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(part, "=")
+		switch {
+		case part == "":
+			return Tag{}, tagError(tag, offset, "empty option")
+		case !ok:
+			return Tag{}, tagError(tag, offset, fmt.Sprintf("option `%s` has no value", part))
+		case value == "":
+			return Tag{}, tagError(tag, offset, fmt.Sprintf("option `%s` has an empty value", key))
+		case seen[key]:
+			return Tag{}, tagError(tag, offset, fmt.Sprintf("option `%s` is set more than once", key))
+		}
+
+		seen[key] = true
+
+		switch key {
+		case tagOptionNamespace:
+			if i := strings.Index(value, separator); i >= 0 {
+				return Tag{}, tagSeparatorError(tag, offset+len(key)+1+i, fmt.Sprintf(
+					"namespace `%s` can't contain the separator (`%s`)",
+					value,
+					separator,
+				))
+			}
+
+			result.Namespace = value
+		case tagOptionProvider:
+			result.Provider = value
+		default:
+			return Tag{}, tagError(tag, offset, fmt.Sprintf("unknown option `%s`", key))
+		}
+
+		offset += len(part) + 1
+	}
+
+	return result, nil
+}
+
+func tagError(tag string, offset int, message string) *SemanticIDError {
+	return &SemanticIDError{
+		errCode: errInvalidTag,
+		message: fmt.Sprintf("Invalid sid tag `%s` at position %d: %s", tag, offset, message),
+	}
+}
+
+// separatorTagError is returned for tags with parts that contain the
+// separator. It matches both ErrInvalidTag and ErrPartContainsSeparator.
+type separatorTagError struct {
+	SemanticIDError
+}
+
+func (err *separatorTagError) Is(target error) bool {
+	return err.SemanticIDError.Is(target) || ErrPartContainsSeparator.Is(target)
+}
+
+func tagSeparatorError(tag string, offset int, message string) error {
+	return &separatorTagError{*tagError(tag, offset, message)}
+}
+
+// isReferenceTag reports whether the tag refers to another model by
+// naming its namespace and collection, e.g. `accounts.users`.
+func isReferenceTag(tag, separator string) bool {
+	name, _, _ := strings.Cut(tag, ",")
+	return strings.Contains(name, separator)
+}
+
+// tagPattern returns the namespace and collection that SemanticIDs in a
+// field with the given tag have to match. Reference tags are parsed like
+// the patterns of the `sid` validation, so either part can be `*`. For
+// all other tags, the namespace only has to match if the `ns` option is
+// set.
+func tagPattern(tag, separator string) (string, string, error) {
+	if !isReferenceTag(tag, separator) {
+		parsed, err := parseTag(tag, separator)
+		if err != nil {
+			return "", "", err
+		}
+
+		if parsed.Namespace == "" {
+			return "*", parsed.Collection, nil
+		}
+
+		return parsed.Namespace, parsed.Collection, nil
+	}
+
+	name, _, hasOptions := strings.Cut(tag, ",")
+	if hasOptions {
+		return "", "", tagError(tag, len(name), "references can't have options")
+	}
+
+	namespace, collection, err := parseSIDPattern(name, separator)
+	if err != nil {
+		return "", "", tagError(tag, 0, err.Error())
+	}
+
+	if namespace == "" || collection == "" {
+		return "", "", tagError(tag, 0, "the reference needs a namespace and a collection")
+	}
+
+	return namespace, collection, nil
+}
+
+// NewForModel creates a unique SemanticID using the collection, namespace
+// and provider defined in the `sid` tag on the `ID` field of the given
+// model.
+func (c *Codec) NewForModel(model interface{}) (SemanticID, error) {
+	tag, err := modelTag(model, "ID", c.separator)
+	if err != nil {
+		return empty, err
+	}
+
+	return c.newForTag(tag)
+}
+
+// newForTag creates a unique SemanticID using the settings from the
+// given tag.
+func (c *Codec) newForTag(tag Tag) (SemanticID, error) {
+	namespace := tag.Namespace
+	if namespace == "" {
+		namespace = c.defaultNamespace
+	}
+
+	if tag.Provider == "" {
+		return c.New(namespace, tag.Collection)
+	}
+
+	idp, err := c.namedProvider(tag.Provider)
+	if err != nil {
+		return empty, err
+	}
+
+	if !c.providers.bindTag(namespace, tag.Collection, idp) {
+		return empty, &SemanticIDError{
+			errCode: errIDProviderError,
+			message: fmt.Sprintf(
+				"`%s%s%s` is bound to a different ID provider than `%s`",
+				namespace,
+				c.separator,
+				tag.Collection,
+				tag.Provider,
+			),
+		}
+	}
+
+	return c.newWithParams(namespace, tag.Collection, idp)
+}
+
+// namedProvider returns the provider registered under the given name in
+// the Codec's registry.
+func (c *Codec) namedProvider(name string) (IDProvider, error) {
+	if c.providers != nil {
+		if idp, ok := c.providers.Provider(name); ok {
+			return idp, nil
+		}
+	}
+
+	return nil, &SemanticIDError{
+		errCode: errIDProviderError,
+		message: fmt.Sprintf("No ID provider registered as `%s`", name),
+	}
+}
@@ -0,0 +1,166 @@
+package semanticid_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+type TaggedModel struct {
+	ID semanticid.SemanticID `sid:"users,ns=accounts,provider=uuid"`
+}
+
+type NamespacedModel struct {
+	ID semanticid.SemanticID `sid:"users,ns=accounts"`
+}
+
+type UnknownProviderModel struct {
+	ID semanticid.SemanticID `sid:"users,provider=unknown"`
+}
+
+var _ = Describe("tag", func() {
+	Describe("Parsing sid tags", func() {
+		It("should parse a collection", func() {
+			tag, err := semanticid.ParseTag("users")
+			Expect(err).To(BeNil())
+			Expect(tag).To(Equal(semanticid.Tag{Collection: "users"}))
+		})
+
+		It("should parse options", func() {
+			tag, err := semanticid.ParseTag("users,ns=accounts,provider=uuid")
+			Expect(err).To(BeNil())
+			Expect(tag).To(Equal(semanticid.Tag{
+				Collection: "users",
+				Namespace:  "accounts",
+				Provider:   "uuid",
+			}))
+
+			tag, err = semanticid.ParseTag("users,provider=uuid")
+			Expect(err).To(BeNil())
+			Expect(tag).To(Equal(semanticid.Tag{Collection: "users", Provider: "uuid"}))
+		})
+
+		It("should point to the malformed part of the tag", func() {
+			cases := map[string]string{
+				"":                          "position 0: the collection is missing",
+				",ns=accounts":              "position 0: the collection is missing",
+				"users,":                    "position 6: empty option",
+				"users,ns":                  "position 6: option `ns` has no value",
+				"users,ns=":                 "position 6: option `ns` has an empty value",
+				"users,ns=a,ns=b":           "position 11: option `ns` is set more than once",
+				"users,ns=accounts,foo=bar": "position 18: unknown option `foo`",
+			}
+
+			for tag, message := range cases {
+				_, err := semanticid.ParseTag(tag)
+				Expect(errors.Is(err, semanticid.ErrInvalidTag)).To(BeTrue(), tag)
+				Expect(err.Error()).To(ContainSubstring(message), tag)
+			}
+		})
+
+		It("should reject the separator in namespace and collection", func() {
+			_, err := semanticid.ParseTag("a.b")
+			Expect(errors.Is(err, semanticid.ErrInvalidTag)).To(BeTrue())
+			Expect(errors.Is(err, semanticid.ErrPartContainsSeparator)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("position 1: collection `a.b` can't contain the separator"))
+
+			_, err = semanticid.ParseTag("users,ns=a.b")
+			Expect(errors.Is(err, semanticid.ErrInvalidTag)).To(BeTrue())
+			Expect(errors.Is(err, semanticid.ErrPartContainsSeparator)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("position 10: namespace `a.b` can't contain the separator"))
+		})
+
+		It("should read tags from models", func() {
+			tag, err := semanticid.TagForModel(&TaggedModel{})
+			Expect(err).To(BeNil())
+			Expect(tag.Namespace).To(Equal("accounts"))
+
+			collection, err := semanticid.CollectionForModel(TaggedModel{})
+			Expect(err).To(BeNil())
+			Expect(collection).To(Equal("users"))
+		})
+	})
+
+	Describe("Creating semanticids for models", func() {
+		It("should use the default namespace and provider without options", func() {
+			sid, err := semanticid.NewForModel(TestModel{})
+			Expect(err).To(BeNil())
+			Expect(sid.Namespace).To(Equal(semanticid.DefaultNamespace))
+			Expect(sid.Collection).To(Equal("testmodels"))
+			Expect(semanticid.DefaultIDProvider.Validate(sid.ID)).To(BeNil())
+		})
+
+		It("should use the namespace from the tag", func() {
+			sid, err := semanticid.NewForModel(&NamespacedModel{})
+			Expect(err).To(BeNil())
+			Expect(sid.Namespace).To(Equal("accounts"))
+			Expect(sid.Collection).To(Equal("users"))
+		})
+
+		It("should use the provider from the tag", func() {
+			sid, err := semanticid.NewForModel(TaggedModel{})
+			Expect(err).To(BeNil())
+			Expect(semanticid.NewUUIDProvider().Validate(sid.ID)).To(BeNil())
+		})
+
+		It("should parse ids created with the provider from the tag", func() {
+			sid, err := semanticid.NewForModel(TaggedModel{})
+			Expect(err).To(BeNil())
+
+			parsed, err := semanticid.FromString(sid.String())
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(sid))
+
+			b, err := json.Marshal(TaggedModel{ID: sid})
+			Expect(err).To(BeNil())
+
+			var result TaggedModel
+			Expect(json.Unmarshal(b, &result)).To(BeNil())
+			Expect(result.ID).To(Equal(sid))
+
+			order := PopulateOrder{Owner: &TaggedModel{}}
+			_, err = semanticid.PopulateIDs(&order)
+			Expect(err).To(BeNil())
+
+			parsed, err = semanticid.FromString(order.Owner.ID.String())
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(order.Owner.ID))
+		})
+
+		It("should reject providers that differ from the registry's binding", func() {
+			registry := semanticid.NewProviderRegistry()
+			codec := semanticid.NewCodec(semanticid.WithProviderRegistry(registry))
+			Expect(codec.BindNamed("accounts.users", "ksuid")).To(BeNil())
+
+			_, err := codec.NewForModel(TaggedModel{})
+			Expect(errors.Is(err, semanticid.ErrIDProvider)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("`accounts.users` is bound to a different ID provider than `uuid`"))
+
+			codec = semanticid.NewCodec(semanticid.WithProviderRegistry(semanticid.NewProviderRegistry()))
+			Expect(codec.BindNamed("accounts.*", "ksuid")).To(BeNil())
+
+			sid, err := codec.NewForModel(TaggedModel{})
+			Expect(err).To(BeNil())
+			Expect(codec.FromString(codec.String(sid))).To(Equal(sid))
+		})
+
+		It("should use the codec's registry for named providers", func() {
+			registry := semanticid.NewProviderRegistry()
+			registry.Register("unknown", &TestProvider{})
+
+			codec := semanticid.NewCodec(semanticid.WithProviderRegistry(registry))
+			sid, err := codec.NewForModel(UnknownProviderModel{})
+			Expect(err).To(BeNil())
+			Expect(sid.ID).To(Equal("1234"))
+		})
+
+		It("should reject unknown providers", func() {
+			_, err := semanticid.NewForModel(UnknownProviderModel{})
+			Expect(errors.Is(err, semanticid.ErrIDProvider)).To(BeTrue())
+		})
+	})
+})
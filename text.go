@@ -0,0 +1,48 @@
+package semanticid
+
+import (
+	"encoding"
+)
+
+var _ encoding.TextMarshaler = &SemanticID{}
+var _ encoding.TextUnmarshaler = &SemanticID{}
+
+// MarshalText implements the encoding.TextMarshaler interface for
+// SemanticID. This allows SemanticIDs to be used as JSON map keys and
+// with text-based encoders such as YAML, TOML or XML.
+func (sid SemanticID) MarshalText() ([]byte, error) {
+	return defaultCodec().EncodeText(sid)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for
+// SemanticID.
+func (sid *SemanticID) UnmarshalText(b []byte) error {
+	return defaultCodec().DecodeText(b, sid)
+}
+
+// EncodeText encodes the given SemanticID as text using the Codec's
+// settings. Nil SemanticIDs are encoded as empty text.
+func (c *Codec) EncodeText(sid SemanticID) ([]byte, error) {
+	if sid.IsNil() {
+		return []byte{}, nil
+	}
+
+	return []byte(c.String(sid)), nil
+}
+
+// DecodeText parses text into the given SemanticID using the
+// Codec's settings. Empty text is decoded into a nil SemanticID.
+func (c *Codec) DecodeText(b []byte, sid *SemanticID) error {
+	if len(b) == 0 {
+		*sid = SemanticID{}
+		return nil
+	}
+
+	parsed, err := c.FromString(string(b))
+	if err != nil {
+		return err
+	}
+
+	*sid = parsed
+	return nil
+}
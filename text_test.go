@@ -0,0 +1,111 @@
+package semanticid_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("text", func() {
+	var sid semanticid.SemanticID
+
+	BeforeEach(func() {
+		sid = semanticid.Must(semanticid.NewDefault())
+	})
+
+	Describe("Marshalling semanticids to text", func() {
+		It("should use the string representation", func() {
+			m, err := sid.MarshalText()
+			Expect(err).To(BeNil())
+			Expect(string(m)).To(Equal(sid.String()))
+		})
+
+		It("should return empty text for a zero value semanticid", func() {
+			m, err := semanticid.SemanticID{}.MarshalText()
+			Expect(err).To(BeNil())
+			Expect(m).To(BeEmpty())
+		})
+	})
+
+	Describe("Unmarshalling semanticids from text", func() {
+		It("should parse valid semanticids", func() {
+			var result semanticid.SemanticID
+			Expect(result.UnmarshalText([]byte(sid.String()))).To(BeNil())
+			Expect(result).To(Equal(sid))
+		})
+
+		It("should reject invalid semanticids", func() {
+			var result semanticid.SemanticID
+			err := result.UnmarshalText([]byte("namespace.collection.1234"))
+			Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+		})
+
+		It("should parse empty text into a nil semanticid", func() {
+			result := sid
+			Expect(result.UnmarshalText([]byte{})).To(BeNil())
+			Expect(result.IsNil()).To(BeTrue())
+		})
+	})
+
+	Describe("Using text-based encoders", func() {
+		It("should round-trip json map keys", func() {
+			other := semanticid.Must(semanticid.NewDefault())
+			val := map[semanticid.SemanticID]int{sid: 1, other: 2}
+
+			m, err := json.Marshal(val)
+			Expect(err).To(BeNil())
+
+			var raw map[string]int
+			Expect(json.Unmarshal(m, &raw)).To(BeNil())
+			Expect(raw).To(HaveKeyWithValue(sid.String(), 1))
+
+			var result map[semanticid.SemanticID]int
+			Expect(json.Unmarshal(m, &result)).To(BeNil())
+			Expect(result).To(Equal(val))
+		})
+
+		It("should still use json strings for values", func() {
+			m, err := json.Marshal(map[string]semanticid.SemanticID{"id": sid})
+			Expect(err).To(BeNil())
+			Expect(string(m)).To(Equal(`{"id":"` + sid.String() + `"}`))
+		})
+
+		It("should round-trip yaml", func() {
+			type doc struct {
+				ID   semanticid.SemanticID         `yaml:"id"`
+				Refs map[semanticid.SemanticID]int `yaml:"refs"`
+			}
+
+			val := doc{ID: sid, Refs: map[semanticid.SemanticID]int{sid: 1}}
+			m, err := yaml.Marshal(val)
+			Expect(err).To(BeNil())
+			Expect(string(m)).To(ContainSubstring("id: " + sid.String()))
+
+			var result doc
+			Expect(yaml.Unmarshal(m, &result)).To(BeNil())
+			Expect(result).To(Equal(val))
+		})
+
+		It("should round-trip xml elements and attributes", func() {
+			type doc struct {
+				XMLName xml.Name              `xml:"doc"`
+				Attr    semanticid.SemanticID `xml:"id,attr"`
+				Elem    semanticid.SemanticID `xml:"ref"`
+			}
+
+			val := doc{XMLName: xml.Name{Local: "doc"}, Attr: sid, Elem: sid}
+			m, err := xml.Marshal(val)
+			Expect(err).To(BeNil())
+
+			var result doc
+			Expect(xml.Unmarshal(m, &result)).To(BeNil())
+			Expect(result).To(Equal(val))
+		})
+	})
+})
@@ -0,0 +1,60 @@
+package semanticid
+
+import (
+	"fmt"
+	"time"
+)
+
+// MinForTime returns the smallest possible SemanticID with the given
+// namespace and collection that could have been created at the given
+// time. This requires an ID provider that implements TimeRangeProvider.
+func MinForTime(namespace, collection string, t time.Time) (SemanticID, error) {
+	return defaultCodec().MinForTime(namespace, collection, t)
+}
+
+// MaxForTime returns the largest possible SemanticID with the given
+// namespace and collection that could have been created at the given
+// time. This requires an ID provider that implements TimeRangeProvider.
+func MaxForTime(namespace, collection string, t time.Time) (SemanticID, error) {
+	return defaultCodec().MaxForTime(namespace, collection, t)
+}
+
+// MinForTime returns the smallest possible SemanticID for the given time,
+// using the ID provider selected by the Codec.
+func (c *Codec) MinForTime(namespace, collection string, t time.Time) (SemanticID, error) {
+	return c.forTime(namespace, collection, func(trp TimeRangeProvider) (string, error) {
+		return trp.MinID(t)
+	})
+}
+
+// MaxForTime returns the largest possible SemanticID for the given time,
+// using the ID provider selected by the Codec.
+func (c *Codec) MaxForTime(namespace, collection string, t time.Time) (SemanticID, error) {
+	return c.forTime(namespace, collection, func(trp TimeRangeProvider) (string, error) {
+		return trp.MaxID(t)
+	})
+}
+
+func (c *Codec) forTime(
+	namespace, collection string,
+	boundary func(TimeRangeProvider) (string, error),
+) (SemanticID, error) {
+	idp := c.providerFor(namespace, collection)
+	trp, ok := idp.(TimeRangeProvider)
+	if !ok {
+		return empty, &SemanticIDError{
+			errCode: errNoTimestamp,
+			message: fmt.Sprintf("ID provider %T doesn't support time ranges", idp),
+		}
+	}
+
+	id, err := boundary(trp)
+	if err != nil {
+		return empty, &SemanticIDError{
+			errCode: errIDProviderError,
+			message: err.Error(),
+		}
+	}
+
+	return c.newFromParts(namespace, collection, id)
+}
@@ -0,0 +1,129 @@
+package semanticid_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/happenslol/semanticid"
+)
+
+var _ = Describe("timerange", func() {
+	t := time.Date(2022, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	type timeRangeProvider interface {
+		semanticid.IDProvider
+		semanticid.TimeRangeProvider
+	}
+
+	cases := []struct {
+		name      string
+		precision time.Duration
+		provider  func(clock func() time.Time) timeRangeProvider
+	}{
+		{"ULID", time.Millisecond, func(clock func() time.Time) timeRangeProvider {
+			return semanticid.NewULIDProvider(semanticid.WithULIDClock(clock))
+		}},
+		{"UUIDv7", time.Millisecond, func(clock func() time.Time) timeRangeProvider {
+			return semanticid.NewUUIDv7Provider(semanticid.WithUUIDv7Clock(clock))
+		}},
+		{"KSUID", time.Second, func(clock func() time.Time) timeRangeProvider {
+			return semanticid.NewKSUIDProvider(semanticid.WithKSUIDClock(clock))
+		}},
+		{"base62 snowflake", time.Millisecond, func(clock func() time.Time) timeRangeProvider {
+			provider, err := semanticid.NewSnowflakeProvider(
+				1,
+				semanticid.WithSnowflakeClock(clock),
+				semanticid.WithSnowflakeEncoding(semanticid.SnowflakeBase62),
+			)
+			Expect(err).To(BeNil())
+			return provider
+		}},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		Describe("Using the "+c.name+" provider", func() {
+			var (
+				now      time.Time
+				provider timeRangeProvider
+			)
+
+			BeforeEach(func() {
+				now = t
+				provider = c.provider(func() time.Time { return now })
+			})
+
+			generateAt := func(at time.Time) string {
+				now = at
+				id, err := provider.Generate()
+				Expect(err).To(BeNil())
+				return id
+			}
+
+			It("should return valid boundary IDs", func() {
+				min, err := provider.MinID(t)
+				Expect(err).To(BeNil())
+				Expect(provider.Validate(min)).To(BeNil())
+
+				max, err := provider.MaxID(t)
+				Expect(err).To(BeNil())
+				Expect(provider.Validate(max)).To(BeNil())
+			})
+
+			It("should include IDs created at the given time", func() {
+				min, err := provider.MinID(t)
+				Expect(err).To(BeNil())
+				max, err := provider.MaxID(t)
+				Expect(err).To(BeNil())
+
+				for i := 0; i < 10; i++ {
+					id := generateAt(t)
+					Expect(id >= min).To(BeTrue())
+					Expect(id <= max).To(BeTrue())
+				}
+			})
+
+			It("should exclude IDs created before or after the given time", func() {
+				min, err := provider.MinID(t)
+				Expect(err).To(BeNil())
+				max, err := provider.MaxID(t)
+				Expect(err).To(BeNil())
+
+				Expect(generateAt(t.Add(-c.precision)) < min).To(BeTrue())
+				Expect(generateAt(t.Add(c.precision)) > max).To(BeTrue())
+			})
+		})
+	}
+
+	Describe("Creating boundary semanticids", func() {
+		It("should use the provider selected for the namespace and collection", func() {
+			min, err := semanticid.MinForTime("shop", "orders", t)
+			Expect(err).To(BeNil())
+			Expect(min.Namespace).To(Equal("shop"))
+			Expect(min.Collection).To(Equal("orders"))
+
+			max, err := semanticid.MaxForTime("shop", "orders", t)
+			Expect(err).To(BeNil())
+			Expect(min.Compare(max)).To(Equal(-1))
+
+			ts, err := min.Timestamp()
+			Expect(err).To(BeNil())
+			Expect(ts.Equal(t)).To(BeTrue())
+		})
+
+		It("should fail for providers that aren't ordered by time", func() {
+			codec := semanticid.NewCodec(semanticid.WithDefaultIDProvider(semanticid.NewUUIDProvider()))
+			_, err := codec.MinForTime("shop", "orders", t)
+			Expect(errors.Is(err, semanticid.ErrNoTimestamp)).To(BeTrue())
+		})
+
+		It("should reject the separator in namespace and collection", func() {
+			_, err := semanticid.MinForTime("shop.eu", "orders", t)
+			Expect(errors.Is(err, semanticid.ErrPartContainsSeparator)).To(BeTrue())
+		})
+	})
+})
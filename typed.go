@@ -0,0 +1,329 @@
+package semanticid
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// A Kind declares the namespace and collection of a typed ID. Kinds are
+// usually empty structs:
+//
+//	type UserKind struct{}
+//
+//	func (UserKind) Namespace() string  { return "accounts" }
+//	func (UserKind) Collection() string { return "users" }
+//
+//	type UserID = semanticid.ID[UserKind]
+//
+// If Namespace returns an empty string, the package-level
+// DefaultNamespace is used.
+type Kind interface {
+	Namespace() string
+	Collection() string
+}
+
+// A KindProvider is a Kind that selects the ID provider for its IDs by
+// the name it was registered under in the provider registry. The
+// provider is used both to generate and to validate IDs of that Kind.
+type KindProvider interface {
+	Kind
+	Provider() string
+}
+
+// ID is a SemanticID that is bound to the namespace and collection of
+// its Kind at compile time, so that IDs of different kinds can't be
+// mixed up. The zero value is a nil ID. Constructors and decoders
+// return ErrWrongKind for SemanticIDs of any other kind.
+type ID[K Kind] struct {
+	sid SemanticID
+}
+
+var _ json.Marshaler = ID[Kind]{}
+var _ json.Unmarshaler = &ID[Kind]{}
+var _ encoding.TextMarshaler = ID[Kind]{}
+var _ encoding.TextUnmarshaler = &ID[Kind]{}
+var _ driver.Valuer = ID[Kind]{}
+var _ sql.Scanner = &ID[Kind]{}
+var _ bsoncodec.ValueMarshaler = ID[Kind]{}
+var _ bsoncodec.ValueUnmarshaler = &ID[Kind]{}
+var _ bsonIDValue = ID[Kind]{}
+var _ bsonIDPointer = &ID[Kind]{}
+
+// kindParts returns the namespace and collection declared by the Kind.
+func kindParts[K Kind](c *Codec) (string, string) {
+	var kind K
+	namespace := kind.Namespace()
+	if namespace == "" {
+		namespace = c.defaultNamespace
+	}
+
+	return namespace, kind.Collection()
+}
+
+// kindCodec returns a Codec that uses the provider selected by the Kind,
+// if it implements KindProvider.
+func kindCodec[K Kind](c *Codec) (*Codec, error) {
+	var kind K
+	kp, ok := interface{}(kind).(KindProvider)
+	if !ok {
+		return c, nil
+	}
+
+	idp, err := c.namedProvider(kp.Provider())
+	if err != nil {
+		return nil, err
+	}
+
+	result := *c
+	result.defaultIDProvider = idp
+	result.providers = nil
+	return &result, nil
+}
+
+// NewID creates a unique ID of the given Kind.
+func NewID[K Kind]() (ID[K], error) {
+	c, err := kindCodec[K](defaultCodec())
+	if err != nil {
+		return ID[K]{}, err
+	}
+
+	sid, err := c.New(kindParts[K](c))
+	if err != nil {
+		return ID[K]{}, err
+	}
+
+	return ID[K]{sid: sid}, nil
+}
+
+// ParseID parses the given string into an ID of the given Kind.
+func ParseID[K Kind](s string) (ID[K], error) {
+	c, err := kindCodec[K](defaultCodec())
+	if err != nil {
+		return ID[K]{}, err
+	}
+
+	sid, err := c.FromString(s)
+	if err != nil {
+		return ID[K]{}, err
+	}
+
+	var result ID[K]
+	return result, result.set(c, sid)
+}
+
+// IDFrom converts an untyped SemanticID into an ID of the given Kind.
+// Nil SemanticIDs are converted into nil IDs.
+func IDFrom[K Kind](sid SemanticID) (ID[K], error) {
+	if err := checkKind[K](defaultCodec(), sid); err != nil {
+		return ID[K]{}, err
+	}
+
+	return ID[K]{sid: sid}, nil
+}
+
+// MustID is a convenience function that converts errors into panics on
+// functions that create or parse an ID.
+func MustID[K Kind](id ID[K], err error) ID[K] {
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+func checkKind[K Kind](c *Codec, sid SemanticID) error {
+	if sid.IsNil() {
+		return nil
+	}
+
+	namespace, collection := kindParts[K](c)
+	if sid.Namespace != namespace || sid.Collection != collection {
+		return &SemanticIDError{
+			errCode: errWrongKind,
+			message: fmt.Sprintf(
+				"%s is not a %s%s%s ID",
+				c.String(sid),
+				namespace,
+				c.separator,
+				collection,
+			),
+		}
+	}
+
+	return nil
+}
+
+// SemanticID returns the untyped SemanticID.
+func (id ID[K]) SemanticID() SemanticID {
+	return id.sid
+}
+
+// IsNil checks whether the ID is nil.
+func (id ID[K]) IsNil() bool {
+	return id.sid.IsNil()
+}
+
+// IsZero reports whether the ID is nil.
+func (id ID[K]) IsZero() bool {
+	return id.sid.IsNil()
+}
+
+// String outputs a string representation of the ID.
+func (id ID[K]) String() string {
+	return id.sid.String()
+}
+
+// Timestamp returns the time at which the ID was created, if its ID
+// provider implements TimestampProvider.
+func (id ID[K]) Timestamp() (time.Time, error) {
+	c, err := kindCodec[K](defaultCodec())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return c.Timestamp(id.sid)
+}
+
+// Equal reports whether both IDs are the same.
+func (id ID[K]) Equal(other ID[K]) bool {
+	c, err := kindCodec[K](defaultCodec())
+	if err != nil {
+		return id.sid.Equal(other.sid)
+	}
+
+	return c.Equal(id.sid, other.sid)
+}
+
+// set assigns the decoded SemanticID after checking its kind.
+func (id *ID[K]) set(c *Codec, sid SemanticID) error {
+	if err := checkKind[K](c, sid); err != nil {
+		return err
+	}
+
+	id.sid = sid
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for ID.
+func (id ID[K]) MarshalJSON() ([]byte, error) {
+	return defaultCodec().EncodeJSON(id.sid)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for ID.
+func (id *ID[K]) UnmarshalJSON(b []byte) error {
+	c, err := kindCodec[K](defaultCodec())
+	if err != nil {
+		return err
+	}
+
+	var sid SemanticID
+	if err = c.DecodeJSON(b, &sid); err != nil {
+		return err
+	}
+
+	return id.set(c, sid)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for ID.
+func (id ID[K]) MarshalText() ([]byte, error) {
+	return defaultCodec().EncodeText(id.sid)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for ID.
+func (id *ID[K]) UnmarshalText(b []byte) error {
+	c, err := kindCodec[K](defaultCodec())
+	if err != nil {
+		return err
+	}
+
+	var sid SemanticID
+	if err = c.DecodeText(b, &sid); err != nil {
+		return err
+	}
+
+	return id.set(c, sid)
+}
+
+// Value implements the driver.Valuer interface for ID.
+func (id ID[K]) Value() (driver.Value, error) {
+	return defaultCodec().EncodeSQL(id.sid)
+}
+
+// Scan implements the sql.Scanner interface for ID.
+func (id *ID[K]) Scan(src interface{}) error {
+	c, err := kindCodec[K](defaultCodec())
+	if err != nil {
+		return err
+	}
+
+	var sid SemanticID
+	if err = c.DecodeSQL(src, &sid); err != nil {
+		return err
+	}
+
+	return id.set(c, sid)
+}
+
+// MarshalBSONValue implements the bsoncodec.ValueMarshaler interface
+// for ID. IDs are stored as strings, unless they are encoded with a
+// registry built by BuildBSONRegistry, which uses the selected storage.
+func (id ID[K]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return defaultCodec().marshalBSONValue(id.sid)
+}
+
+// UnmarshalBSONValue implements the bsoncodec.ValueUnmarshaler
+// interface for ID. All forms written by the BSON codecs are accepted.
+func (id *ID[K]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	c, err := kindCodec[K](defaultCodec())
+	if err != nil {
+		return err
+	}
+
+	sid, err := c.unmarshalBSONValue(t, data)
+	if err != nil {
+		return err
+	}
+
+	return id.set(c, sid)
+}
+
+// encodeBSONID writes the ID for the BSON codecs, using their storage.
+func (id ID[K]) encodeBSONID(c *Codec, vw bsonrw.ValueWriter, storage BSONStorage) error {
+	if id.sid.IsNil() {
+		return c.writeBSONNil(vw)
+	}
+
+	return c.writeBSON(vw, id.sid, storage)
+}
+
+// decodeBSONID reads the ID for the BSON codecs.
+func (id *ID[K]) decodeBSONID(c *Codec, vr bsonrw.ValueReader) error {
+	c, err := kindCodec[K](c)
+	if err != nil {
+		return err
+	}
+
+	switch vr.Type() {
+	case bsontype.Null:
+		id.sid = empty
+		return vr.ReadNull()
+	case bsontype.Undefined:
+		id.sid = empty
+		return vr.ReadUndefined()
+	}
+
+	sid, err := c.readBSON(vr)
+	if err != nil {
+		return err
+	}
+
+	return id.set(c, sid)
+}
@@ -0,0 +1,284 @@
+package semanticid_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/happenslol/semanticid"
+)
+
+type userKind struct{}
+
+func (userKind) Namespace() string  { return "people" }
+func (userKind) Collection() string { return "users" }
+
+type orderKind struct{}
+
+func (orderKind) Namespace() string  { return "shop" }
+func (orderKind) Collection() string { return "orders" }
+
+type defaultNamespaceKind struct{}
+
+func (defaultNamespaceKind) Namespace() string  { return "" }
+func (defaultNamespaceKind) Collection() string { return "things" }
+
+type legacyKind struct{}
+
+func (legacyKind) Namespace() string  { return "legacy" }
+func (legacyKind) Collection() string { return "users" }
+func (legacyKind) Provider() string   { return "uuid" }
+
+type unknownProviderKind struct{}
+
+func (unknownProviderKind) Namespace() string  { return "legacy" }
+func (unknownProviderKind) Collection() string { return "users" }
+func (unknownProviderKind) Provider() string   { return "unknown" }
+
+type userID = semanticid.ID[userKind]
+
+var _ = Describe("typed ids", func() {
+	var (
+		user  userID
+		order semanticid.ID[orderKind]
+	)
+
+	BeforeEach(func() {
+		user = semanticid.MustID(semanticid.NewID[userKind]())
+		order = semanticid.MustID(semanticid.NewID[orderKind]())
+	})
+
+	Describe("Creating typed ids", func() {
+		It("should use the kind's namespace and collection", func() {
+			sid := user.SemanticID()
+			Expect(sid.Namespace).To(Equal("people"))
+			Expect(sid.Collection).To(Equal("users"))
+			Expect(semanticid.DefaultIDProvider.Validate(sid.ID)).To(BeNil())
+		})
+
+		It("should use the default namespace if the kind doesn't declare one", func() {
+			id := semanticid.MustID(semanticid.NewID[defaultNamespaceKind]())
+			Expect(id.SemanticID().Namespace).To(Equal(semanticid.DefaultNamespace))
+			Expect(id.SemanticID().Collection).To(Equal("things"))
+		})
+
+		It("should use the provider selected by the kind", func() {
+			id, err := semanticid.NewID[legacyKind]()
+			Expect(err).To(BeNil())
+			Expect(semanticid.NewUUIDProvider().Validate(id.SemanticID().ID)).To(BeNil())
+
+			parsed, err := semanticid.ParseID[legacyKind](id.String())
+			Expect(err).To(BeNil())
+			Expect(parsed).To(Equal(id))
+
+			_, err = semanticid.NewID[unknownProviderKind]()
+			Expect(errors.Is(err, semanticid.ErrIDProvider)).To(BeTrue())
+		})
+
+		It("should start out nil", func() {
+			var id userID
+			Expect(id.IsNil()).To(BeTrue())
+			Expect(id.IsZero()).To(BeTrue())
+		})
+	})
+
+	Describe("Converting typed ids", func() {
+		It("should convert to and from untyped semanticids", func() {
+			result, err := semanticid.IDFrom[userKind](user.SemanticID())
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(user))
+			Expect(result.Equal(user)).To(BeTrue())
+			Expect(result.String()).To(Equal(user.SemanticID().String()))
+
+			_, err = semanticid.IDFrom[userKind](order.SemanticID())
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+
+			result, err = semanticid.IDFrom[userKind](semanticid.SemanticID{})
+			Expect(err).To(BeNil())
+			Expect(result.IsNil()).To(BeTrue())
+		})
+
+		It("should parse strings", func() {
+			result, err := semanticid.ParseID[userKind](user.String())
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(user))
+
+			_, err = semanticid.ParseID[userKind](order.String())
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+
+			_, err = semanticid.ParseID[userKind]("people.users.1234")
+			Expect(errors.Is(err, semanticid.ErrInvalidIDPart)).To(BeTrue())
+		})
+
+		It("should return the creation time", func() {
+			expected, err := user.SemanticID().Timestamp()
+			Expect(err).To(BeNil())
+
+			ts, err := user.Timestamp()
+			Expect(err).To(BeNil())
+			Expect(ts).To(Equal(expected))
+		})
+	})
+
+	Describe("Encoding typed ids", func() {
+		type model struct {
+			User  userID                   `json:"user" bson:"user"`
+			Order semanticid.ID[orderKind] `json:"order" bson:"order"`
+		}
+
+		It("should round-trip json", func() {
+			original := model{User: user, Order: order}
+			m, err := json.Marshal(original)
+			Expect(err).To(BeNil())
+			Expect(string(m)).To(ContainSubstring(`"user":"` + user.String() + `"`))
+
+			var result model
+			Expect(json.Unmarshal(m, &result)).To(BeNil())
+			Expect(result).To(Equal(original))
+
+			m, err = json.Marshal(model{})
+			Expect(err).To(BeNil())
+			Expect(json.Unmarshal(m, &result)).To(BeNil())
+			Expect(result).To(Equal(model{}))
+		})
+
+		It("should reject the wrong kind in json", func() {
+			m, err := json.Marshal(map[string]interface{}{"user": order})
+			Expect(err).To(BeNil())
+
+			var result model
+			err = json.Unmarshal(m, &result)
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+		})
+
+		It("should round-trip bson without registering codecs", func() {
+			original := model{User: user, Order: order}
+			m, err := bson.Marshal(original)
+			Expect(err).To(BeNil())
+
+			var raw map[string]string
+			Expect(bson.Unmarshal(m, &raw)).To(BeNil())
+			Expect(raw["user"]).To(Equal(user.String()))
+
+			var result model
+			Expect(bson.Unmarshal(m, &result)).To(BeNil())
+			Expect(result).To(Equal(original))
+
+			m, err = bson.Marshal(model{})
+			Expect(err).To(BeNil())
+			Expect(bson.Unmarshal(m, &result)).To(BeNil())
+			Expect(result).To(Equal(model{}))
+		})
+
+		It("should read semanticids written by the bson codecs", func() {
+			reg := semanticid.BuildBSONRegistry(nil, semanticid.WithBSONStorage(semanticid.BSONStorageBinary))
+			m, err := bson.MarshalWithRegistry(reg, bson.M{"user": user.SemanticID()})
+			Expect(err).To(BeNil())
+
+			var result model
+			Expect(bson.Unmarshal(m, &result)).To(BeNil())
+			Expect(result.User).To(Equal(user))
+		})
+
+		It("should use the storage of the bson codecs", func() {
+			for _, storage := range []semanticid.BSONStorage{
+				semanticid.BSONStorageString,
+				semanticid.BSONStorageDocument,
+				semanticid.BSONStorageBinary,
+			} {
+				reg := semanticid.BuildBSONRegistry(nil, semanticid.WithBSONStorage(storage))
+				expected, err := bson.MarshalWithRegistry(reg, bson.M{"user": user.SemanticID()})
+				Expect(err).To(BeNil())
+
+				m, err := bson.MarshalWithRegistry(reg, bson.M{"user": user})
+				Expect(err).To(BeNil())
+				Expect(m).To(Equal(expected))
+
+				ptr, err := bson.MarshalWithRegistry(reg, bson.M{"user": &user})
+				Expect(err).To(BeNil())
+				Expect(ptr).To(Equal(expected))
+
+				original := model{User: user, Order: order}
+				m, err = bson.MarshalWithRegistry(reg, original)
+				Expect(err).To(BeNil())
+
+				var result model
+				Expect(bson.UnmarshalWithRegistry(reg, m, &result)).To(BeNil())
+				Expect(result).To(Equal(original))
+
+				var pointers struct {
+					User  *userID `bson:"user"`
+					Order *userID `bson:"order"`
+				}
+				m, err = bson.MarshalWithRegistry(reg, bson.M{"user": user, "order": nil})
+				Expect(err).To(BeNil())
+				Expect(bson.UnmarshalWithRegistry(reg, m, &pointers)).To(BeNil())
+				Expect(*pointers.User).To(Equal(user))
+				Expect(pointers.Order).To(BeNil())
+			}
+		})
+
+		It("should use the codec's settings in bson registries", func() {
+			codec := semanticid.NewCodec(semanticid.WithSeparator(":"))
+			reg := codec.BuildBSONRegistry(nil)
+
+			m, err := bson.MarshalWithRegistry(reg, model{User: user, Order: order})
+			Expect(err).To(BeNil())
+			Expect(bson.Raw(m).Lookup("user").StringValue()).To(Equal(codec.String(user.SemanticID())))
+
+			var result model
+			Expect(bson.UnmarshalWithRegistry(reg, m, &result)).To(BeNil())
+			Expect(result.User).To(Equal(user))
+		})
+
+		It("should reject the wrong kind with the bson codecs", func() {
+			reg := semanticid.BuildBSONRegistry(nil, semanticid.WithBSONStorage(semanticid.BSONStorageBinary))
+			m, err := bson.MarshalWithRegistry(reg, bson.M{"user": order})
+			Expect(err).To(BeNil())
+
+			var result model
+			err = bson.UnmarshalWithRegistry(reg, m, &result)
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+		})
+
+		It("should reject the wrong kind in bson", func() {
+			m, err := bson.Marshal(bson.M{"user": order.String()})
+			Expect(err).To(BeNil())
+
+			var result model
+			err = bson.Unmarshal(m, &result)
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+		})
+
+		It("should convert to and from database values", func() {
+			value, err := user.Value()
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(user.String()))
+
+			var result userID
+			Expect(result.Scan(value)).To(BeNil())
+			Expect(result).To(Equal(user))
+
+			Expect(result.Scan(nil)).To(BeNil())
+			Expect(result.IsNil()).To(BeTrue())
+
+			err = result.Scan(order.String())
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+		})
+
+		It("should convert to and from text", func() {
+			m, err := user.MarshalText()
+			Expect(err).To(BeNil())
+
+			var result userID
+			Expect(result.UnmarshalText(m)).To(BeNil())
+			Expect(result).To(Equal(user))
+
+			err = result.UnmarshalText([]byte(order.String()))
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+		})
+	})
+})
@@ -20,33 +20,144 @@ func validateSIDPrefix(sid SemanticID, namespace, collection string) bool {
 	return true
 }
 
-func SemanticIDValidation(fl validator.FieldLevel) bool {
-	raw := fl.Field().Interface()
-
-	param := fl.Param()
-	namespace := "*"
-	collection := "*"
-	parts := strings.Split(param, Separator)
-
-	//NOTE(happens): We panic here in case of an invalid param,
-	// similar to how the baked in validators handle this case
+// parseSIDPattern splits a pattern of the form `namespace.collection`
+// into its parts. Either part can be `*` to match anything, and a pattern
+// without a separator only specifies the collection.
+func parseSIDPattern(pattern, separator string) (string, string, error) {
+	parts := strings.Split(pattern, separator)
 	switch len(parts) {
 	case 2:
-		namespace = parts[0]
-		collection = parts[1]
+		return parts[0], parts[1], nil
 	case 1:
 		if parts[0] == "" {
-			panic("Expected an argument for sid validator")
+			return "", "", fmt.Errorf("Expected a namespace or collection")
 		}
 
-		collection = parts[0]
-	default:
+		return "*", parts[0], nil
+	}
+
+	return "", "", fmt.Errorf("Pattern `%s` contains more than one separator", pattern)
+}
+
+// A FieldMismatch describes a SemanticID field that doesn't belong to
+// the namespace and collection declared in its `sid` tag.
+type FieldMismatch struct {
+	// Path names the field, e.g. `Items[0].OwnerID`
+	Path string
+	// Pattern is the expected `namespace.collection`, where either part
+	// can be `*`
+	Pattern string
+	Actual  SemanticID
+}
+
+// A ModelValidationError lists every mismatched field found by
+// ValidateModel. It matches ErrWrongKind when using errors.Is.
+type ModelValidationError struct {
+	Fields []FieldMismatch
+
+	codec *Codec
+}
+
+func (err *ModelValidationError) Error() string {
+	mismatches := make([]string, len(err.Fields))
+	for i, f := range err.Fields {
+		mismatches[i] = fmt.Sprintf(
+			"`%s` should match `%s`, got `%s`",
+			f.Path,
+			f.Pattern,
+			err.codec.String(f.Actual),
+		)
+	}
+
+	return fmt.Sprintf("SemanticIDs don't match their sid tags: %s", strings.Join(mismatches, ", "))
+}
+
+func (err *ModelValidationError) Is(target error) bool {
+	return ErrWrongKind.Is(target)
+}
+
+// ValidateModel walks the given model recursively, including nested
+// structs, slices, arrays and pointers, and checks that every SemanticID
+// field with an `sid` tag belongs to the namespace and collection the tag
+// declares. Tags can either be reference patterns like `accounts.users`
+// or regular model tags. Nil fields are skipped, use the `required`
+// validation for those. Mismatches are returned as a
+// *ModelValidationError.
+func ValidateModel(model interface{}) error {
+	return defaultCodec().ValidateModel(model)
+}
+
+// ValidateModel works like the package-level ValidateModel, using the
+// Codec's separator.
+func (c *Codec) ValidateModel(model interface{}) error {
+	val := reflect.ValueOf(model)
+	if reflect.Indirect(val).Kind() != reflect.Struct {
+		return fmt.Errorf("ValidateModel needs a struct or a pointer to one, got %T", model)
+	}
+
+	var mismatches []FieldMismatch
+	err := walkModel(val, func(field reflect.Value, path, tag string) error {
+		var sid SemanticID
+		switch value := field.Interface().(type) {
+		case SemanticID:
+			sid = value
+		case *SemanticID:
+			if value != nil {
+				sid = *value
+			}
+		}
+
+		namespace, collection, err := tagPattern(tag, c.separator)
+		if err != nil {
+			return fmt.Errorf("Field `%s`: %w", path, err)
+		}
+
+		if !sid.IsNil() && !validateSIDPrefix(sid, namespace, collection) {
+			mismatches = append(mismatches, FieldMismatch{
+				Path:    path,
+				Pattern: namespace + c.separator + collection,
+				Actual:  sid,
+			})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(mismatches) > 0 {
+		return &ModelValidationError{Fields: mismatches, codec: c}
+	}
+
+	return nil
+}
+
+func SemanticIDValidation(fl validator.FieldLevel) bool {
+	return defaultCodec().SemanticIDValidation(fl)
+}
+
+// SemanticIDValidation validates fields against the pattern given in the
+// `sid` validation tag, using the Codec's separator and ID providers.
+func (c *Codec) SemanticIDValidation(fl validator.FieldLevel) bool {
+	raw := fl.Field().Interface()
+
+	//NOTE(happens): We panic here in case of an invalid param,
+	// similar to how the baked in validators handle this case
+	param := fl.Param()
+	if param == "" {
+		panic("Expected an argument for sid validator")
+	}
+
+	namespace, collection, err := parseSIDPattern(param, c.separator)
+	if err != nil {
 		panic(fmt.Sprintf("Bad sid validation argument: %s", param))
 	}
 
 	switch value := raw.(type) {
 	case string:
-		sid, err := FromString(value)
+		sid, err := c.FromString(value)
 		if err != nil {
 			return false
 		}
@@ -54,7 +165,7 @@ func SemanticIDValidation(fl validator.FieldLevel) bool {
 		return validateSIDPrefix(sid, namespace, collection)
 	case []string:
 		for _, s := range value {
-			sid, err := FromString(s)
+			sid, err := c.FromString(s)
 			if err != nil {
 				return false
 			}
@@ -71,6 +182,12 @@ func SemanticIDValidation(fl validator.FieldLevel) bool {
 }
 
 func SemanticIDTypeFunc(field reflect.Value) interface{} {
+	return defaultCodec().SemanticIDTypeFunc(field)
+}
+
+// SemanticIDTypeFunc converts SemanticID fields to their string
+// representation using the Codec's separator.
+func (c *Codec) SemanticIDTypeFunc(field reflect.Value) interface{} {
 	raw := field.Interface()
 	switch value := raw.(type) {
 	case SemanticID:
@@ -78,20 +195,20 @@ func SemanticIDTypeFunc(field reflect.Value) interface{} {
 			return ""
 		}
 
-		return value.String()
+		return c.String(value)
 	case *SemanticID:
 		if value.IsNil() {
 			return ""
 		}
 
-		return value.String()
+		return c.String(*value)
 	case []SemanticID:
 		idStrs := make([]string, len(value))
 		for i, id := range value {
 			if id.IsNil() {
 				idStrs[i] = ""
 			} else {
-				idStrs[i] = id.String()
+				idStrs[i] = c.String(id)
 			}
 		}
 
@@ -102,7 +219,7 @@ func SemanticIDTypeFunc(field reflect.Value) interface{} {
 			if id.IsNil() {
 				idStrs[i] = ""
 			} else {
-				idStrs[i] = id.String()
+				idStrs[i] = c.String(*id)
 			}
 		}
 
@@ -113,13 +230,19 @@ func SemanticIDTypeFunc(field reflect.Value) interface{} {
 }
 
 func RegisterValidation(v *validator.Validate) error {
+	return defaultCodec().RegisterValidation(v)
+}
+
+// RegisterValidation registers the `sid` validation tag on the given
+// validator, using the Codec's settings.
+func (c *Codec) RegisterValidation(v *validator.Validate) error {
 	v.RegisterCustomTypeFunc(
-		SemanticIDTypeFunc,
+		c.SemanticIDTypeFunc,
 		SemanticID{},
 		&SemanticID{},
 		[]SemanticID{},
 		[]*SemanticID{},
 	)
 
-	return v.RegisterValidation("sid", SemanticIDValidation)
+	return v.RegisterValidation("sid", c.SemanticIDValidation)
 }
@@ -1,7 +1,9 @@
 package semanticid_test
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -23,6 +25,19 @@ type TestValidation struct {
 	PointerArray []*semanticid.SemanticID `validate:"sid=testcol"`
 }
 
+type ReferencingModel struct {
+	ID       semanticid.SemanticID   `sid:"orders,ns=shop"`
+	OwnerID  semanticid.SemanticID   `sid:"accounts.users"`
+	Reviewer *semanticid.SemanticID  `sid:"*.users"`
+	Watchers []semanticid.SemanticID `sid:"accounts.users"`
+	Items    []ReferencedItem
+	Parent   *ReferencingModel
+}
+
+type ReferencedItem struct {
+	ProductID semanticid.SemanticID `sid:"products"`
+}
+
 type NoArgValidation struct {
 	Test semanticid.SemanticID `validate:"sid"`
 }
@@ -121,7 +136,7 @@ var _ = Describe("validator", func() {
 						fmt.Printf("%v\n", err)
 					}
 				}
-				Expect(fn).To(Panic())
+				Expect(fn).To(PanicWith("Expected an argument for sid validator"))
 			})
 
 			It("should panic when an argument with more than 1 separator is passed", func() {
@@ -130,8 +145,89 @@ var _ = Describe("validator", func() {
 						fmt.Printf("%v\n", err)
 					}
 				}
-				Expect(fn).To(Panic())
+				Expect(fn).To(PanicWith("Bad sid validation argument: 1.2.3.4"))
 			})
 		})
 	})
+
+	Describe("Validating models against their sid tags", func() {
+		var model *ReferencingModel
+
+		BeforeEach(func() {
+			reviewer := semanticid.Must(semanticid.New("support", "users"))
+			model = &ReferencingModel{
+				ID:       semanticid.Must(semanticid.New("shop", "orders")),
+				OwnerID:  semanticid.Must(semanticid.New("accounts", "users")),
+				Reviewer: &reviewer,
+				Watchers: []semanticid.SemanticID{
+					semanticid.Must(semanticid.New("accounts", "users")),
+				},
+				Items: []ReferencedItem{
+					{ProductID: semanticid.Must(semanticid.New("catalog", "products"))},
+				},
+			}
+		})
+
+		It("should accept matching fields", func() {
+			Expect(semanticid.ValidateModel(model)).To(BeNil())
+			Expect(semanticid.ValidateModel(*model)).To(BeNil())
+		})
+
+		It("should skip nil fields", func() {
+			Expect(semanticid.ValidateModel(&ReferencingModel{})).To(BeNil())
+		})
+
+		It("should list every mismatched field", func() {
+			order := semanticid.Must(semanticid.New("shop", "orders"))
+			model.OwnerID = order
+			model.Watchers = append(model.Watchers, order)
+			model.Items[0].ProductID = order
+			model.Parent = &ReferencingModel{ID: semanticid.Must(semanticid.New("other", "orders"))}
+			model.Parent.Parent = model
+
+			err := semanticid.ValidateModel(model)
+			Expect(errors.Is(err, semanticid.ErrWrongKind)).To(BeTrue())
+
+			var validationErr *semanticid.ModelValidationError
+			Expect(errors.As(err, &validationErr)).To(BeTrue())
+			Expect(validationErr.Fields).To(Equal([]semanticid.FieldMismatch{
+				{Path: "OwnerID", Pattern: "accounts.users", Actual: order},
+				{Path: "Watchers[1]", Pattern: "accounts.users", Actual: order},
+				{Path: "Items[0].ProductID", Pattern: "*.products", Actual: order},
+				{Path: "Parent.ID", Pattern: "shop.orders", Actual: model.Parent.ID},
+			}))
+			Expect(err.Error()).To(ContainSubstring(
+				"`OwnerID` should match `accounts.users`, got `" + order.String() + "`",
+			))
+		})
+
+		It("should use the codec's separator", func() {
+			codec := semanticid.NewCodec(semanticid.WithSeparator(":"))
+			model := struct {
+				OwnerID semanticid.SemanticID `sid:"accounts:users"`
+			}{semanticid.Must(codec.New("accounts", "orders"))}
+
+			err := codec.ValidateModel(model)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("should match `accounts:users`, got `accounts:orders:"))
+		})
+
+		It("should reject invalid tags", func() {
+			for _, tag := range []string{"a.b.c", "accounts.users,ns=a", ".users", "users,foo=bar"} {
+				model := reflect.New(reflect.StructOf([]reflect.StructField{{
+					Name: "OwnerID",
+					Type: reflect.TypeOf(semanticid.SemanticID{}),
+					Tag:  reflect.StructTag(`sid:"` + tag + `"`),
+				}}))
+
+				err := semanticid.ValidateModel(model.Interface())
+				Expect(errors.Is(err, semanticid.ErrInvalidTag)).To(BeTrue(), tag)
+				Expect(err.Error()).To(ContainSubstring("OwnerID"), tag)
+			}
+		})
+
+		It("should only accept structs", func() {
+			Expect(semanticid.ValidateModel("accounts.users")).NotTo(BeNil())
+		})
+	})
 })